@@ -0,0 +1,151 @@
+package serrors
+
+import (
+	"iter"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// DuplicatePolicy controls how attributes with the same key, attached at
+// different levels of an error chain, are collected.
+type DuplicatePolicy int
+
+const (
+	// OutermostWins keeps the attribute closest to the top of the chain.
+	OutermostWins DuplicatePolicy = iota
+	// InnermostWins keeps the attribute closest to the root cause.
+	InnermostWins
+	// KeepAll keeps every attribute, prefixing the keys of nested ones with
+	// CauseKey once per level, e.g. "cause.cause.user_id".
+	KeepAll
+)
+
+// Attrs returns the attributes of every structured error in the chain of
+// err, outermost first. Duplicate keys are resolved with OutermostWins.
+func Attrs(err error) []slog.Attr {
+	return CollectAttrs(err, OutermostWins)
+}
+
+// CollectAttrs returns the attributes of every structured error in the chain
+// of err, resolving duplicate keys according to policy.
+func CollectAttrs(err error, policy DuplicatePolicy) []slog.Attr {
+	var attrs []slog.Attr
+
+	switch policy {
+	case InnermostWins:
+		index := map[string]int{}
+		for attr := range AllAttrs(err) {
+			if i, ok := index[attr.Key]; ok {
+				attrs[i] = attr
+				continue
+			}
+
+			index[attr.Key] = len(attrs)
+			attrs = append(attrs, attr)
+		}
+	case KeepAll:
+		walk(err, func(s serror, depth int) bool {
+			prefix := strings.Repeat(CauseKey+".", depth)
+			for _, attr := range s.attrs {
+				attr.Key = prefix + attr.Key
+				attrs = append(attrs, attr)
+			}
+
+			return true
+		})
+	default:
+		seen := map[string]struct{}{}
+		for attr := range AllAttrs(err) {
+			if _, ok := seen[attr.Key]; ok {
+				continue
+			}
+
+			seen[attr.Key] = struct{}{}
+			attrs = append(attrs, attr)
+		}
+	}
+
+	return attrs
+}
+
+// AllAttrs returns an iterator over the attributes of every structured error
+// in the chain of err, outermost first. Duplicate keys are not resolved.
+func AllAttrs(err error) iter.Seq[slog.Attr] {
+	return func(yield func(slog.Attr) bool) {
+		walk(err, func(s serror, _ int) bool {
+			for _, attr := range s.attrs {
+				if !yield(attr) {
+					return false
+				}
+			}
+
+			return true
+		})
+	}
+}
+
+// argsToAttrs converts alternating keys and values to attributes, following
+// the rules of [slog.Logger].
+func argsToAttrs(args []any) []slog.Attr {
+	if len(args) == 0 {
+		return nil
+	}
+
+	return slog.Group("", args...).Value.Group()
+}
+
+// mergeAttrs returns a new slice with the attributes of base, replaced or
+// followed by those of extra. Attributes with an empty key, such as options
+// and inlined groups, are never replaced.
+func mergeAttrs(base, extra []slog.Attr) []slog.Attr {
+	merged := slices.Clone(base)
+
+	for _, attr := range extra {
+		i := slices.IndexFunc(merged, func(a slog.Attr) bool {
+			return a.Key != "" && a.Key == attr.Key
+		})
+
+		if i >= 0 {
+			merged[i] = attr
+		} else {
+			merged = append(merged, attr)
+		}
+	}
+
+	return merged
+}
+
+// walk calls fn for every serror in the chain of err, outermost first,
+// following both Unwrap() error and Unwrap() []error. depth is the number of
+// serrors above the current one. Walking stops when fn returns false.
+func walk(err error, fn func(s serror, depth int) bool) bool {
+	return walkDepth(err, 0, fn)
+}
+
+func walkDepth(err error, depth int, fn func(s serror, depth int) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	if s, ok := asSerror(err); ok {
+		if !fn(s, depth) {
+			return false
+		}
+
+		depth++
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkDepth(e.Unwrap(), depth, fn)
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			if !walkDepth(err, depth, fn) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,146 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+func TestCollectAttrs(t *testing.T) {
+	inner := NewError("validation failed",
+		slog.String("field", "email"),
+		slog.String("request_id", "inner"))
+	middle := WrapError("request processing failed", inner,
+		slog.String("request_id", "req-123"))
+	outer := WrapError("handler error", middle, slog.String("handler", "UserHandler"))
+
+	tests := []struct {
+		name     string
+		err      error
+		policy   DuplicatePolicy
+		expected []slog.Attr
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			policy:   OutermostWins,
+			expected: nil,
+		},
+		{
+			name:     "standard error",
+			err:      errors.New("plain"),
+			policy:   OutermostWins,
+			expected: nil,
+		},
+		{
+			name:   "outermost wins",
+			err:    outer,
+			policy: OutermostWins,
+			expected: []slog.Attr{
+				slog.String("handler", "UserHandler"),
+				slog.String("request_id", "req-123"),
+				slog.String("field", "email"),
+			},
+		},
+		{
+			name:   "innermost wins",
+			err:    outer,
+			policy: InnermostWins,
+			expected: []slog.Attr{
+				slog.String("handler", "UserHandler"),
+				slog.String("request_id", "inner"),
+				slog.String("field", "email"),
+			},
+		},
+		{
+			name:   "keep all",
+			err:    outer,
+			policy: KeepAll,
+			expected: []slog.Attr{
+				slog.String("handler", "UserHandler"),
+				slog.String("cause.request_id", "req-123"),
+				slog.String("cause.cause.field", "email"),
+				slog.String("cause.cause.request_id", "inner"),
+			},
+		},
+		{
+			name:   "through fmt.Errorf",
+			err:    fmt.Errorf("fetch: %w", middle),
+			policy: OutermostWins,
+			expected: []slog.Attr{
+				slog.String("request_id", "req-123"),
+				slog.String("field", "email"),
+			},
+		},
+		{
+			name: "through errors.Join",
+			err: errors.Join(
+				NewError("a", slog.String("user_id", "1")),
+				errors.New("b"),
+				NewError("c", slog.String("tenant", "acme"), slog.String("user_id", "2")),
+			),
+			policy: OutermostWins,
+			expected: []slog.Attr{
+				slog.String("user_id", "1"),
+				slog.String("tenant", "acme"),
+			},
+		},
+		{
+			name: "keep all through errors.Join",
+			err: WrapError("batch failed", errors.Join(
+				NewError("a", slog.String("user_id", "1")),
+				NewError("c", slog.String("user_id", "2")),
+			), slog.Int("size", 2)),
+			policy: KeepAll,
+			expected: []slog.Attr{
+				slog.Int("size", 2),
+				slog.String("cause.user_id", "1"),
+				slog.String("cause.user_id", "2"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := CollectAttrs(tt.err, tt.policy)
+			if !slices.EqualFunc(actual, tt.expected, slog.Attr.Equal) {
+				t.Errorf("CollectAttrs() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAttrs(t *testing.T) {
+	err := WrapError("outer", NewError("inner", slog.String("key", "inner")),
+		slog.String("key", "outer"))
+
+	expected := []slog.Attr{slog.String("key", "outer")}
+	if actual := Attrs(err); !slices.EqualFunc(actual, expected, slog.Attr.Equal) {
+		t.Errorf("Attrs() = %v, want %v", actual, expected)
+	}
+}
+
+func TestAllAttrs(t *testing.T) {
+	err := WrapError("outer", NewError("inner", slog.String("key", "inner")),
+		slog.String("key", "outer"), slog.Int("count", 1))
+
+	expected := []slog.Attr{
+		slog.String("key", "outer"),
+		slog.Int("count", 1),
+		slog.String("key", "inner"),
+	}
+	if actual := slices.Collect(AllAttrs(err)); !slices.EqualFunc(actual, expected, slog.Attr.Equal) {
+		t.Errorf("AllAttrs() = %v, want %v", actual, expected)
+	}
+
+	var first []slog.Attr
+	for attr := range AllAttrs(err) {
+		first = append(first, attr)
+		break
+	}
+	if len(first) != 1 || !first[0].Equal(expected[0]) {
+		t.Errorf("AllAttrs() did not stop early: %v", first)
+	}
+}
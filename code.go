@@ -0,0 +1,134 @@
+package serrors
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CodeKey is the key under which the code of an error is logged.
+var CodeKey = "code"
+
+// Code returns an option that sets the machine-readable code of an error.
+// Pass it to the constructors among the attributes:
+//
+//	serrors.NewError("user not found", serrors.Code("user.not_found"))
+func Code(code string) slog.Attr {
+	return optionAttr(func(o *options) {
+		o.code = code
+	})
+}
+
+// CodeOf returns the code of the outermost error in the chain of err that
+// has one.
+func CodeOf(err error) string {
+	var code string
+
+	walk(err, func(s serror, _ int) bool {
+		code = s.code
+		return code == ""
+	})
+
+	return code
+}
+
+// CodeInfo describes a registered code.
+type CodeInfo struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+	// Status is the HTTP status of errors with this code, used by Problem. It
+	// must be between 400 and 599.
+	Status int `json:"status,omitempty"`
+}
+
+var registry = struct {
+	sync.RWMutex
+	codes map[string]CodeInfo
+}{codes: map[string]CodeInfo{}}
+
+// Register adds a code to the process-wide catalog and returns it. It is
+// meant to be called while initializing package variables, and panics if the
+// code is empty or already registered, or if its status is set but is not an
+// HTTP error status.
+func Register(info CodeInfo) string {
+	if info.Code == "" {
+		panic("serrors: Register called with an empty code")
+	}
+
+	if info.Status != 0 && !errorStatus(int64(info.Status)) {
+		panic(fmt.Sprintf("serrors: code %q registered with status %d", info.Code, info.Status))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, exists := registry.codes[info.Code]; exists {
+		panic(fmt.Sprintf("serrors: code %q registered twice", info.Code))
+	}
+
+	registry.codes[info.Code] = info
+
+	return info.Code
+}
+
+// LookupCode returns the registered description of code.
+func LookupCode(code string) (CodeInfo, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	info, ok := registry.codes[code]
+
+	return info, ok
+}
+
+// Catalog returns all registered codes, sorted by code.
+func Catalog() []CodeInfo {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	infos := make([]CodeInfo, 0, len(registry.codes))
+	for _, info := range registry.codes {
+		infos = append(infos, info)
+	}
+
+	slices.SortFunc(infos, func(a, b CodeInfo) int {
+		return cmp.Compare(a.Code, b.Code)
+	})
+
+	return infos
+}
+
+// WriteCatalogJSON writes the catalog of registered codes to w as a JSON
+// array.
+func WriteCatalogJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(Catalog())
+}
+
+// WriteCatalogMarkdown writes the catalog of registered codes to w as a
+// Markdown table.
+func WriteCatalogMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	_, _ = b.WriteString("| Code | Description |\n")
+	_, _ = b.WriteString("| --- | --- |\n")
+
+	for _, info := range Catalog() {
+		_, _ = b.WriteString("| `" + info.Code + "` | " + markdownCell(info.Description) + " |\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
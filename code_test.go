@@ -0,0 +1,168 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+var (
+	testCodeNotFound = Register(CodeInfo{Code: "test.not_found", Description: "The resource does not exist"})
+	testCodePipe     = Register(CodeInfo{Code: "test.pipe", Description: "Contains a | pipe"})
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "no code",
+			err:      NewError("test error"),
+			expected: "",
+		},
+		{
+			name:     "NewError with code",
+			err:      NewError("user not found", Code(testCodeNotFound), slog.String("user_id", "123")),
+			expected: testCodeNotFound,
+		},
+		{
+			name:     "code of inner error",
+			err:      fmt.Errorf("handler: %w", WrapError("fetch failed", NewError("user not found", Code("inner")))),
+			expected: "inner",
+		},
+		{
+			name:     "outermost code wins",
+			err:      WrapError("fetch failed", NewError("user not found", Code("inner")), Code("outer")),
+			expected: "outer",
+		},
+		{
+			name:     "standard error",
+			err:      errors.New("plain"),
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := CodeOf(tt.err); actual != tt.expected {
+				t.Errorf("CodeOf() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCode_ErrorAndLogValue(t *testing.T) {
+	err := NewError("user not found", Code(testCodeNotFound), slog.String("user_id", "123"))
+
+	if err.Error() != "user not found user_id=123" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "user not found user_id=123")
+	}
+	if err.(Error).Code() != testCodeNotFound {
+		t.Errorf("Code() = %q, want %q", err.(Error).Code(), testCodeNotFound)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("operation failed", "error", err)
+
+	var logOutput map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &logOutput); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	errorGroup, ok := logOutput["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected 'error' to be a group, got %T", logOutput["error"])
+	}
+	if errorGroup[CodeKey] != testCodeNotFound {
+		t.Errorf("Expected code %q, got %v", testCodeNotFound, errorGroup[CodeKey])
+	}
+}
+
+func TestRegister_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		info CodeInfo
+	}{
+		{
+			name: "duplicate code",
+			info: CodeInfo{Code: testCodeNotFound},
+		},
+		{
+			name: "empty code",
+			info: CodeInfo{Description: "no code"},
+		},
+		{
+			name: "not an error status",
+			info: CodeInfo{Code: "test.ok", Status: 200},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected Register to panic")
+				}
+			}()
+
+			Register(tt.info)
+		})
+	}
+}
+
+func TestLookupCode(t *testing.T) {
+	info, ok := LookupCode(testCodeNotFound)
+	if !ok || info.Description != "The resource does not exist" {
+		t.Errorf("LookupCode() = %v, %v", info, ok)
+	}
+
+	if _, ok := LookupCode("test.unregistered"); ok {
+		t.Errorf("LookupCode() found an unregistered code")
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	catalog := Catalog()
+
+	for i := 1; i < len(catalog); i++ {
+		if catalog[i-1].Code >= catalog[i].Code {
+			t.Errorf("Catalog is not sorted: %q before %q", catalog[i-1].Code, catalog[i].Code)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCatalogJSON(&buf); err != nil {
+		t.Fatalf("WriteCatalogJSON() error = %v", err)
+	}
+
+	var decoded []CodeInfo
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Failed to parse JSON catalog: %v", err)
+	}
+	if len(decoded) != len(catalog) {
+		t.Errorf("JSON catalog has %d codes, want %d", len(decoded), len(catalog))
+	}
+
+	buf.Reset()
+	if err := WriteCatalogMarkdown(&buf); err != nil {
+		t.Fatalf("WriteCatalogMarkdown() error = %v", err)
+	}
+
+	markdown := buf.String()
+	for _, row := range []string{
+		"| Code | Description |\n| --- | --- |\n",
+		"| `test.not_found` | The resource does not exist |\n",
+		"| `test.pipe` | Contains a \\| pipe |\n",
+	} {
+		if !strings.Contains(markdown, row) {
+			t.Errorf("Markdown catalog missing %q:\n%s", row, markdown)
+		}
+	}
+}
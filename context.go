@@ -0,0 +1,46 @@
+package serrors
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+type contextKey struct{}
+
+// ContextWith returns a copy of ctx carrying attrs, in addition to those
+// already stored on ctx. Attributes with the same key replace the stored ones.
+func ContextWith(ctx context.Context, attrs ...slog.Attr) context.Context {
+	stored, _ := ctx.Value(contextKey{}).([]slog.Attr)
+
+	return context.WithValue(ctx, contextKey{}, mergeAttrs(stored, attrs))
+}
+
+// ContextAttrs returns the attributes stored on ctx by [ContextWith].
+func ContextAttrs(ctx context.Context) []slog.Attr {
+	stored, _ := ctx.Value(contextKey{}).([]slog.Attr)
+
+	return slices.Clone(stored)
+}
+
+// NewErrorContext is like [NewError], but also attaches the attributes stored
+// on ctx. Attributes passed explicitly override those with the same key.
+func NewErrorContext(ctx context.Context, msg string, attrs ...slog.Attr) error {
+	return newError(1, serror{msg: msg, attrs: contextAttrs(ctx, attrs)})
+}
+
+// WrapErrorContext is like [WrapError], but also attaches the attributes
+// stored on ctx. Attributes passed explicitly override those with the same
+// key.
+func WrapErrorContext(ctx context.Context, msg string, err error, attrs ...slog.Attr) error {
+	return newError(1, nilCause(serror{msg: msg, err: err, attrs: contextAttrs(ctx, attrs)}))
+}
+
+func contextAttrs(ctx context.Context, attrs []slog.Attr) []slog.Attr {
+	stored, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	if len(stored) == 0 {
+		return attrs
+	}
+
+	return mergeAttrs(stored, attrs)
+}
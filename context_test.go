@@ -0,0 +1,91 @@
+package serrors
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestContextWith(t *testing.T) {
+	ctx := ContextWith(context.Background(),
+		slog.String("request_id", "req-1"),
+		slog.String("tenant", "acme"))
+	child := ContextWith(ctx, slog.String("user_id", "u-1"), slog.String("tenant", "other"))
+
+	expected := "request_id=req-1 tenant=acme"
+	if actual := attrsString(ContextAttrs(ctx)); actual != expected {
+		t.Errorf("ContextAttrs(parent) = %q, want %q", actual, expected)
+	}
+
+	expected = "request_id=req-1 tenant=other user_id=u-1"
+	if actual := attrsString(ContextAttrs(child)); actual != expected {
+		t.Errorf("ContextAttrs(child) = %q, want %q", actual, expected)
+	}
+
+	if ContextAttrs(context.Background()) != nil {
+		t.Errorf("Expected no attributes on an empty context")
+	}
+}
+
+func TestErrorContext(t *testing.T) {
+	ctx := ContextWith(context.Background(),
+		slog.String("request_id", "req-1"),
+		slog.String("tenant", "acme"))
+	cause := errors.New("timeout")
+
+	tests := []struct {
+		name     string
+		create   func() error
+		expected string
+	}{
+		{
+			name: "NewErrorContext",
+			create: func() error {
+				return NewErrorContext(ctx, "user not found", slog.String("user_id", "u-1"))
+			},
+			expected: "user not found request_id=req-1 tenant=acme user_id=u-1",
+		},
+		{
+			name: "explicit attributes override the context",
+			create: func() error {
+				return NewErrorContext(ctx, "user not found", slog.String("tenant", "other"))
+			},
+			expected: "user not found request_id=req-1 tenant=other",
+		},
+		{
+			name: "WrapErrorContext",
+			create: func() error {
+				return WrapErrorContext(ctx, "fetch failed", cause, Code("fetch"), CallerSkip(0))
+			},
+			expected: "fetch failed cause=[timeout] request_id=req-1 tenant=acme",
+		},
+		{
+			name: "context without attributes",
+			create: func() error {
+				return WrapErrorContext(context.Background(), "fetch failed", cause, slog.Int("retry", 1))
+			},
+			expected: "fetch failed cause=[timeout] retry=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.create().Error(); actual != tt.expected {
+				t.Errorf("Error() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func attrsString(attrs []slog.Attr) string {
+	var s string
+	for i, attr := range attrs {
+		if i > 0 {
+			s += " "
+		}
+		s += attr.String()
+	}
+
+	return s
+}
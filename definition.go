@@ -0,0 +1,81 @@
+package serrors
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// Definition is a predefined error, usable as a sentinel with [errors.Is].
+// Every error created from it matches it, while carrying its own attributes
+// and cause:
+//
+//	var ErrNotFound = serrors.Define("not found", serrors.Code("not_found"))
+//
+//	err := ErrNotFound.New(slog.String("user_id", id))
+//	errors.Is(err, ErrNotFound) // true
+type Definition struct {
+	s serror
+}
+
+// Define creates a new Definition with the given message and attributes.
+// Options such as [Code] are inherited by every error created from it.
+func Define(msg string, attrs ...slog.Attr) *Definition {
+	d := &Definition{}
+
+	var opts options
+	d.s.msg = msg
+	d.s.attrs, opts = extractOptions(attrs)
+	d.s.code = opts.code
+	d.s.def = d
+
+	return d
+}
+
+// Error implements error.
+func (d *Definition) Error() string {
+	return d.s.Error()
+}
+
+func (d *Definition) LogValue() slog.Value {
+	return d.s.LogValue()
+}
+
+// Code returns the code of the definition, or an empty string.
+func (d *Definition) Code() string {
+	return d.s.code
+}
+
+// New creates an error from the definition, with additional attributes.
+func (d *Definition) New(attrs ...slog.Attr) error {
+	return newError(1, d.instance(nil, attrs))
+}
+
+// Wrap creates an error from the definition that wraps err, with additional
+// attributes.
+func (d *Definition) Wrap(err error, attrs ...slog.Attr) error {
+	return newError(1, nilCause(d.instance(err, attrs)))
+}
+
+func (d *Definition) instance(err error, attrs []slog.Attr) serror {
+	s := d.s
+	s.err = err
+	s.attrs = slices.Concat(d.s.attrs, attrs)
+
+	return s
+}
+
+// Is reports whether s was created from the target [Definition], or shares
+// its code with the target.
+func (s serror) Is(target error) bool {
+	if d, ok := target.(*Definition); ok {
+		if s.def == d {
+			return true
+		}
+
+		target = d.s
+	}
+
+	t, ok := asSerror(target)
+
+	return ok && s.code != "" && s.code == t.code
+}
@@ -0,0 +1,125 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+var (
+	errTestNotFound  = Define("not found", Code("test.definition.not_found"), slog.String("kind", "lookup"))
+	errTestForbidden = Define("forbidden")
+	errTestDuplicate = Define("duplicate not found", Code("test.definition.not_found"))
+)
+
+func TestDefinition_Is(t *testing.T) {
+	cause := errors.New("no rows")
+
+	tests := []struct {
+		name     string
+		err      error
+		target   error
+		expected bool
+	}{
+		{
+			name:     "definition itself",
+			err:      errTestNotFound,
+			target:   errTestNotFound,
+			expected: true,
+		},
+		{
+			name:     "instance with attrs",
+			err:      errTestNotFound.New(slog.String("user_id", "123")),
+			target:   errTestNotFound,
+			expected: true,
+		},
+		{
+			name:     "instance wrapping a cause",
+			err:      errTestNotFound.Wrap(cause),
+			target:   errTestNotFound,
+			expected: true,
+		},
+		{
+			name:     "instance still matches its cause",
+			err:      errTestNotFound.Wrap(cause),
+			target:   cause,
+			expected: true,
+		},
+		{
+			name:     "instance deep in the chain",
+			err:      fmt.Errorf("handler: %w", WrapError("fetch failed", errTestForbidden.New())),
+			target:   errTestForbidden,
+			expected: true,
+		},
+		{
+			name:     "different definition",
+			err:      errTestForbidden.New(),
+			target:   errTestNotFound,
+			expected: false,
+		},
+		{
+			name:     "definition with the same code",
+			err:      errTestDuplicate.New(),
+			target:   errTestNotFound,
+			expected: true,
+		},
+		{
+			name:     "plain error with the same code",
+			err:      NewError("missing", Code("test.definition.not_found")),
+			target:   errTestNotFound,
+			expected: true,
+		},
+		{
+			name:     "serrors with the same code",
+			err:      NewError("missing", Code("test.definition.not_found")),
+			target:   NewError("other", Code("test.definition.not_found")),
+			expected: true,
+		},
+		{
+			name:     "serrors without code",
+			err:      NewError("missing"),
+			target:   NewError("missing"),
+			expected: false,
+		},
+		{
+			name:     "definition without code and plain error",
+			err:      NewError("forbidden"),
+			target:   errTestForbidden,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := errors.Is(tt.err, tt.target); actual != tt.expected {
+				t.Errorf("errors.Is() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDefinition_Instance(t *testing.T) {
+	err := errTestNotFound.Wrap(errors.New("no rows"), slog.String("user_id", "123"))
+
+	expected := "not found cause=[no rows] kind=lookup user_id=123"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+	if CodeOf(err) != "test.definition.not_found" {
+		t.Errorf("CodeOf() = %q, want %q", CodeOf(err), "test.definition.not_found")
+	}
+	if errTestNotFound.Error() != "not found kind=lookup" {
+		t.Errorf("Definition Error() = %q, want %q", errTestNotFound.Error(), "not found kind=lookup")
+	}
+	if errTestNotFound.Code() != "test.definition.not_found" {
+		t.Errorf("Definition Code() = %q", errTestNotFound.Code())
+	}
+
+	// Instances must not share their attribute storage.
+	first := errTestNotFound.New(slog.String("n", "1"))
+	second := errTestNotFound.New(slog.String("n", "2"))
+	if first.Error() != "not found kind=lookup n=1" || second.Error() != "not found kind=lookup n=2" {
+		t.Errorf("Instances share attributes: %q, %q", first.Error(), second.Error())
+	}
+}
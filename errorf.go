@@ -0,0 +1,43 @@
+package serrors
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Errorf formats according to a format specifier, like [fmt.Errorf], and
+// returns a structured error. The operands of %w verbs become its causes, so
+// [errors.Is] and [errors.As] behave as with fmt.Errorf. Arguments of type
+// [slog.Attr] are not formatted, and are attached as attributes instead:
+//
+//	serrors.Errorf("fetch %s: %w", name, err, slog.String("user_id", id))
+//
+// The text of the causes is part of the formatted message, so Error renders
+// the same text as fmt.Errorf followed by the attributes.
+func Errorf(format string, args ...any) error {
+	var (
+		attrs []slog.Attr
+		rest  = make([]any, 0, len(args))
+	)
+
+	for _, arg := range args {
+		if attr, ok := arg.(slog.Attr); ok {
+			attrs = append(attrs, attr)
+		} else {
+			rest = append(rest, arg)
+		}
+	}
+
+	wrapped := fmt.Errorf(format, rest...)
+	s := serror{msg: wrapped.Error(), attrs: attrs, inline: true}
+
+	switch e := wrapped.(type) {
+	case interface{ Unwrap() error }:
+		s.err = e.Unwrap()
+	case interface{ Unwrap() []error }:
+		s.errs = e.Unwrap()
+		return newError(1, s).multi()
+	}
+
+	return newError(1, s)
+}
@@ -0,0 +1,104 @@
+package serrors
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestErrorf(t *testing.T) {
+	errA := errors.New("timeout")
+	errB := errors.New("invalid")
+
+	tests := []struct {
+		name           string
+		create         func() error
+		expected       string
+		expectedCauses []error
+		expectedAttrs  []string
+	}{
+		{
+			name: "no verbs",
+			create: func() error {
+				return Errorf("user not found")
+			},
+			expected: "user not found",
+		},
+		{
+			name: "formatted message with attrs",
+			create: func() error {
+				return Errorf("user %s not found", "john", slog.String("table", "users"))
+			},
+			expected:      "user john not found table=users",
+			expectedAttrs: []string{"table"},
+		},
+		{
+			name: "single %w",
+			create: func() error {
+				return Errorf("fetch %s: %w", "users", errA, slog.Int("retry", 3))
+			},
+			expected:       "fetch users: timeout retry=3",
+			expectedCauses: []error{errA},
+			expectedAttrs:  []string{"retry"},
+		},
+		{
+			name: "attrs before operands",
+			create: func() error {
+				return Errorf("fetch %s: %w", slog.Int("retry", 3), "users", errA)
+			},
+			expected:       "fetch users: timeout retry=3",
+			expectedCauses: []error{errA},
+			expectedAttrs:  []string{"retry"},
+		},
+		{
+			name: "multiple %w",
+			create: func() error {
+				return Errorf("fetch: %w, %w", errA, errB, Code("fetch"), slog.Int("retry", 3))
+			},
+			expected:       "fetch: timeout, invalid retry=3",
+			expectedCauses: []error{errA, errB},
+			expectedAttrs:  []string{"retry"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.create()
+
+			if err.Error() != tt.expected {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.expected)
+			}
+
+			for _, cause := range tt.expectedCauses {
+				if !errors.Is(err, cause) {
+					t.Errorf("Expected errors.Is to find %v", cause)
+				}
+			}
+
+			var serr Error
+			if !errors.As(err, &serr) {
+				t.Fatalf("Expected errors.As to find Error")
+			}
+			if len(serr.Causes()) != len(tt.expectedCauses) {
+				t.Errorf("Causes() = %v, want %v", serr.Causes(), tt.expectedCauses)
+			}
+
+			attrs := Attrs(err)
+			if len(attrs) != len(tt.expectedAttrs) {
+				t.Fatalf("Attrs() = %v, want keys %v", attrs, tt.expectedAttrs)
+			}
+			for i, key := range tt.expectedAttrs {
+				if attrs[i].Key != key {
+					t.Errorf("Attrs()[%d].Key = %q, want %q", i, attrs[i].Key, key)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorf_Code(t *testing.T) {
+	err := Errorf("fetch: %w", errors.New("timeout"), Code("fetch"))
+	if CodeOf(err) != "fetch" {
+		t.Errorf("CodeOf() = %q, want %q", CodeOf(err), "fetch")
+	}
+}
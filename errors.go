@@ -2,62 +2,360 @@ package serrors
 
 import (
 	"log/slog"
+	"runtime"
+	"slices"
 	"strings"
 )
 
+// Error is the structured error produced by [NewError] and [WrapError]. Use
+// [errors.As] to retrieve it from an error chain.
+type Error interface {
+	error
+
+	// Message returns the message of this error, without its cause or
+	// attributes.
+	Message() string
+	// Cause returns the wrapped error, or nil. Errors created by [WrapErrors]
+	// have no single cause; use Causes instead.
+	Cause() error
+	// Causes returns all wrapped errors.
+	Causes() []error
+	// Attrs returns the attributes attached to this error.
+	Attrs() []slog.Attr
+	// Code returns the machine-readable code of this error, or an empty
+	// string.
+	Code() string
+	// StackTrace returns the stack captured when this error was created, or
+	// nil if none was captured.
+	StackTrace() []runtime.Frame
+	// Source returns the caller recorded when this error was created, or nil
+	// if none was recorded.
+	Source() *slog.Source
+}
+
 type serror struct {
 	msg   string
 	err   error
+	errs  []error
 	attrs []slog.Attr
+	code  string
+	def   *Definition
+	stack *stack
+	pc    uintptr
+	// src is the source of an error decoded from another process, for which
+	// no program counter is available.
+	src *slog.Source
+
+	// inline reports that the message already contains the text of the
+	// causes, which are therefore not rendered again. An empty message stands
+	// for the text of the cause.
+	inline bool
 }
 
 // Error implements error.
 func (s serror) Error() string {
+	if ErrorRendering == RenderV2 {
+		return s.quotedError()
+	}
+
 	var b strings.Builder
 
-	_, _ = b.WriteString(s.msg)
+	_, _ = b.WriteString(s.Message())
 
-	if s.err != nil {
+	if s.err != nil && !s.inline {
 		_ = b.WriteByte(' ')
 		_, _ = b.WriteString(CauseKey + "=[" + s.err.Error() + "]")
 	}
 
+	if len(s.errs) > 0 && !s.inline {
+		_ = b.WriteByte(' ')
+		_, _ = b.WriteString(CausesKey + "=" + causeList(s.errs).String())
+	}
+
 	for _, attr := range s.attrs {
 		_ = b.WriteByte(' ')
 		_, _ = b.WriteString(attr.String())
 	}
 
+	if SourceInError && s.hasSource() {
+		_ = b.WriteByte(' ')
+		_, _ = b.WriteString(slog.SourceKey + "=" + sourceString(s.Source()))
+	}
+
 	return b.String()
 }
 
 func (s serror) LogValue() slog.Value {
-	size := len(s.attrs) + 1
-	if s.err != nil {
+	return s.logValue(DefaultLogMode)
+}
+
+func (s serror) logValue(mode LogMode) slog.Value {
+	if mode == LogFlat {
+		return flatValue(s)
+	}
+
+	return s.nestedValue()
+}
+
+// nestedValue returns the value of s with its causes nested under CauseKey
+// or CausesKey.
+func (s serror) nestedValue() slog.Value {
+	var causes []slog.Attr
+
+	// Causes already rendered in the message are only logged when they have
+	// structure of their own.
+	if s.err != nil && (!s.inline || hasLogValuer(s.err)) {
+		causes = append(causes, slog.Attr{Key: CauseKey, Value: causeValue(s.err)})
+	}
+
+	if len(s.errs) > 0 && (!s.inline || slices.ContainsFunc(s.errs, hasLogValuer)) {
+		causes = append(causes, slog.Any(CausesKey, causeList(s.errs)))
+	}
+
+	return slog.GroupValue(s.levelAttrs(causes...)...)
+}
+
+// levelAttrs returns the logged attributes of s alone, with the given causes
+// placed after the message and code.
+func (s serror) levelAttrs(causes ...slog.Attr) []slog.Attr {
+	size := len(s.attrs) + len(causes) + 1
+	if s.code != "" {
+		size++
+	}
+	if s.stack != nil {
+		size++
+	}
+	if s.hasSource() {
 		size++
 	}
 
 	attrs := make([]slog.Attr, 0, size)
-	attrs = append(attrs, slog.String(slog.MessageKey, s.msg))
+	attrs = append(attrs, slog.String(slog.MessageKey, s.Message()))
 
-	if s.err != nil {
-		attrs = append(attrs, slog.Any(CauseKey, s.err))
+	if s.code != "" {
+		attrs = append(attrs, slog.String(CodeKey, s.code))
 	}
 
+	attrs = append(attrs, causes...)
 	attrs = append(attrs, s.attrs...)
 
-	return slog.GroupValue(attrs...)
+	if s.hasSource() {
+		attrs = append(attrs, sourceAttr(s.Source()))
+	}
+
+	if s.stack != nil {
+		attrs = append(attrs, slog.Any(StackKey, s.stack.strings()))
+	}
+
+	return attrs
 }
 
 func (e serror) Unwrap() error {
 	return e.err
 }
 
-var CauseKey = "cause"
+// Message implements Error.
+func (s serror) Message() string {
+	if s.inline && s.msg == "" && s.err != nil {
+		return s.err.Error()
+	}
+
+	return s.msg
+}
+
+// Cause implements Error.
+func (s serror) Cause() error {
+	return s.err
+}
+
+// Causes implements Error.
+func (s serror) Causes() []error {
+	if s.errs != nil {
+		return slices.Clone(s.errs)
+	}
+
+	if s.err != nil {
+		return []error{s.err}
+	}
+
+	return nil
+}
+
+// Attrs implements Error.
+func (s serror) Attrs() []slog.Attr {
+	return slices.Clone(s.attrs)
+}
+
+// Code implements Error.
+func (s serror) Code() string {
+	return s.code
+}
+
+// StackTrace implements Error.
+func (s serror) StackTrace() []runtime.Frame {
+	if s.stack == nil {
+		return nil
+	}
+
+	return s.stack.frames()
+}
+
+// Source implements Error.
+func (s serror) Source() *slog.Source {
+	if s.src != nil {
+		return s.src
+	}
+
+	return pcSource(s.pc)
+}
+
+func (s serror) hasSource() bool {
+	return s.pc != 0 || s.src != nil
+}
+
+// multiError is a serror with several causes. It unwraps to all of them,
+// like the result of [errors.Join].
+type multiError struct {
+	serror
+}
+
+func (m multiError) Unwrap() []error {
+	return m.errs
+}
+
+// multi returns s as a multiError.
+func (s serror) multi() multiError {
+	return multiError{s}
+}
+
+var (
+	CauseKey  = "cause"
+	CausesKey = "causes"
+)
 
 func NewError(msg string, attrs ...slog.Attr) error {
-	return serror{msg: msg, attrs: attrs}
+	return newError(1, serror{msg: msg, attrs: attrs})
 }
 
 func WrapError(msg string, err error, attrs ...slog.Attr) error {
-	return serror{msg: msg, err: err, attrs: attrs}
+	return newError(1, nilCause(serror{msg: msg, err: err, attrs: attrs}))
+}
+
+// New is like [NewError], but takes alternating keys and values the way
+// [slog.Logger.Info] does. A slog.Attr argument is used as is, and a
+// missing key is replaced with "!BADKEY".
+func New(msg string, args ...any) error {
+	return newError(1, serror{msg: msg, attrs: argsToAttrs(args)})
+}
+
+// Wrap is like [WrapError], but takes alternating keys and values the way
+// [slog.Logger.Info] does.
+func Wrap(msg string, err error, args ...any) error {
+	return newError(1, nilCause(serror{msg: msg, err: err, attrs: argsToAttrs(args)}))
+}
+
+// WrapIf is like [WrapError], but returns nil when err is nil.
+func WrapIf(msg string, err error, attrs ...slog.Attr) error {
+	if err == nil {
+		return nil
+	}
+
+	return newError(1, serror{msg: msg, err: err, attrs: attrs})
+}
+
+// Annotate wraps the error pointed to by errp with [WrapError], unless it is
+// nil. It is meant to be deferred in functions with a named error result:
+//
+//	func load(path string) (err error) {
+//		defer serrors.Annotate(&err, "load config", slog.String("path", path))
+//		...
+//	}
+//
+// When caller or stack capture is enabled, the location recorded is the exit
+// of the deferring function.
+func Annotate(errp *error, msg string, attrs ...slog.Attr) {
+	if *errp == nil {
+		return
+	}
+
+	*errp = newError(1, serror{msg: msg, err: *errp, attrs: attrs})
+}
+
+// WrapErrors wraps several errors at once. The result unwraps to all non-nil
+// errors in errs, so [errors.Is] and [errors.As] traverse every branch.
+func WrapErrors(msg string, errs []error, attrs ...slog.Attr) error {
+	var causes []error
+	for _, err := range errs {
+		if err != nil {
+			causes = append(causes, err)
+		}
+	}
+
+	return multiError{newError(1, serror{msg: msg, errs: causes, attrs: attrs})}
+}
+
+// With attaches attributes to err without adding a message. If err is a
+// structured error, the result is a copy of it with the attributes merged in,
+// replacing those with the same key. Otherwise err is wrapped transparently:
+// the result renders the text of err followed by the attributes, and unwraps
+// to err. With returns nil if err is nil.
+func With(err error, attrs ...slog.Attr) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case serror:
+		return e.with(attrs)
+	case multiError:
+		return e.with(attrs).multi()
+	default:
+		return newError(1, serror{err: err, attrs: attrs, inline: true})
+	}
+}
+
+func (s serror) with(attrs []slog.Attr) serror {
+	attrs, opts := extractOptions(attrs)
+
+	s.attrs = mergeAttrs(s.attrs, attrs)
+	if opts.code != "" {
+		s.code = opts.code
+	}
+
+	return s
+}
+
+// newError finishes building s, applying the options found in its attributes
+// and capturing its caller and stack. skip is the number of frames between
+// newError and the caller the error should be attributed to.
+func newError(skip int, s serror) serror {
+	var opts options
+	s.attrs, opts = extractOptions(s.attrs)
+	skip += opts.skip
+
+	if opts.code != "" {
+		s.code = opts.code
+	}
+
+	if CaptureSource {
+		s.pc = captureCaller(skip + 1)
+	}
+
+	if shouldCaptureStack(s.Causes()) {
+		s.stack = captureStack(skip + 1)
+	}
+
+	return s
+}
+
+// asSerror returns the serror behind err, if err is one.
+func asSerror(err error) (serror, bool) {
+	switch e := err.(type) {
+	case serror:
+		return e, true
+	case multiError:
+		return e.serror, true
+	case *Definition:
+		return e.s, true
+	default:
+		return serror{}, false
+	}
 }
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -742,3 +744,550 @@ func TestSerror_Error_CommonExpectations(t *testing.T) {
 		})
 	}
 }
+
+func TestError_Accessors(t *testing.T) {
+	cause := errors.New("connection refused")
+	tests := []struct {
+		name          string
+		create        func() error
+		expectedMsg   string
+		expectedCause error
+		expectedAttrs []slog.Attr
+	}{
+		{
+			name: "NewError",
+			create: func() error {
+				return NewError("user not found", slog.String("user_id", "123"))
+			},
+			expectedMsg:   "user not found",
+			expectedAttrs: []slog.Attr{slog.String("user_id", "123")},
+		},
+		{
+			name: "WrapError",
+			create: func() error {
+				return WrapError("failed to save user", cause, slog.Int("retry", 3))
+			},
+			expectedMsg:   "failed to save user",
+			expectedCause: cause,
+			expectedAttrs: []slog.Attr{slog.Int("retry", 3)},
+		},
+		{
+			name: "wrapped with fmt.Errorf",
+			create: func() error {
+				return fmt.Errorf("handler: %w", NewError("validation failed"))
+			},
+			expectedMsg: "validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var target Error
+			if !errors.As(tt.create(), &target) {
+				t.Fatalf("Expected errors.As to find Error in chain")
+			}
+
+			if target.Message() != tt.expectedMsg {
+				t.Errorf("Message() = %q, want %q", target.Message(), tt.expectedMsg)
+			}
+			if target.Cause() != tt.expectedCause {
+				t.Errorf("Cause() = %v, want %v", target.Cause(), tt.expectedCause)
+			}
+
+			attrs := target.Attrs()
+			if len(attrs) != len(tt.expectedAttrs) {
+				t.Fatalf("Attrs() = %v, want %v", attrs, tt.expectedAttrs)
+			}
+			for i := range attrs {
+				if !attrs[i].Equal(tt.expectedAttrs[i]) {
+					t.Errorf("Attrs()[%d] = %v, want %v", i, attrs[i], tt.expectedAttrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestError_Attrs_Copy(t *testing.T) {
+	err := NewError("test error", slog.String("key", "value"))
+
+	var target Error
+	if !errors.As(err, &target) {
+		t.Fatalf("Expected errors.As to find Error in chain")
+	}
+
+	target.Attrs()[0] = slog.String("key", "modified")
+
+	if err.Error() != "test error key=value" {
+		t.Errorf("Modifying Attrs() result changed the error: %q", err.Error())
+	}
+}
+
+func TestWrapErrors(t *testing.T) {
+	errA := errors.New("a")
+	errB := NewError("b", slog.String("key", "value"))
+
+	err := WrapErrors("fetch failed", []error{errA, nil, errB}, slog.Int("count", 2))
+
+	expected := "fetch failed causes=[[a] [b key=value]] count=2"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+
+	unwrapper, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Expected WrapErrors result to implement Unwrap() []error")
+	}
+	if len(unwrapper.Unwrap()) != 2 {
+		t.Errorf("Unwrap() = %v, want 2 errors", unwrapper.Unwrap())
+	}
+
+	if !errors.Is(err, errA) {
+		t.Errorf("Expected errors.Is to find the first cause")
+	}
+
+	var target Error
+	if !errors.As(err, &target) || target.Message() != "fetch failed" {
+		t.Fatalf("Expected errors.As to find the outer Error")
+	}
+	if target.Cause() != nil {
+		t.Errorf("Cause() = %v, want nil", target.Cause())
+	}
+	if len(target.Causes()) != 2 {
+		t.Errorf("Causes() = %v, want 2 errors", target.Causes())
+	}
+
+	attrs := Attrs(err)
+	if len(attrs) != 2 || attrs[0].Key != "count" || attrs[1].Key != "key" {
+		t.Errorf("Attrs() = %v, want count and key", attrs)
+	}
+}
+
+func TestWrapErrors_Empty(t *testing.T) {
+	err := WrapErrors("nothing failed", []error{nil})
+
+	if err.Error() != "nothing failed" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "nothing failed")
+	}
+	if len(err.(Error).Causes()) != 0 {
+		t.Errorf("Causes() = %v, want none", err.(Error).Causes())
+	}
+}
+
+func TestWrapErrors_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	err := WrapErrors("batch validation failed", []error{
+		errors.New("plain failure"),
+		NewError("invalid email",
+			slog.String("field", "email"),
+			slog.Int("row", 3),
+			slog.Group("limits", slog.Int("max", 10))),
+	}, slog.String("batch", "b-1"))
+
+	logger.Error("batch failed", "error", err)
+
+	var logOutput map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &logOutput); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	errorGroup, ok := logOutput["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected 'error' to be a group, got %T", logOutput["error"])
+	}
+	if errorGroup["batch"] != "b-1" {
+		t.Errorf("Expected batch 'b-1', got %v", errorGroup["batch"])
+	}
+
+	causes, ok := errorGroup["causes"].([]any)
+	if !ok || len(causes) != 2 {
+		t.Fatalf("Expected 'causes' to be an array of 2, got %T: %v", errorGroup["causes"], errorGroup["causes"])
+	}
+
+	if causes[0] != "plain failure" {
+		t.Errorf("Expected first cause 'plain failure', got %v", causes[0])
+	}
+
+	second, ok := causes[1].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected second cause to be an object, got %T", causes[1])
+	}
+	expected := map[string]any{
+		"msg":    "invalid email",
+		"field":  "email",
+		"row":    float64(3),
+		"limits": map[string]any{"max": float64(10)},
+	}
+	if len(second) != len(expected) {
+		t.Errorf("Second cause = %v, want %v", second, expected)
+	}
+	for key, value := range expected {
+		if m, ok := value.(map[string]any); ok {
+			if actual, _ := second[key].(map[string]any); actual == nil || actual["max"] != m["max"] {
+				t.Errorf("Second cause '%s' = %v, want %v", key, second[key], value)
+			}
+		} else if second[key] != value {
+			t.Errorf("Second cause '%s' = %v, want %v", key, second[key], value)
+		}
+	}
+}
+
+func TestSerror_LogValue_StandardWrappers(t *testing.T) {
+	inner := NewError("validation failed", slog.String("field", "email"))
+
+	tests := []struct {
+		name     string
+		err      error
+		expected any
+	}{
+		{
+			name: "fmt.Errorf around serror",
+			err:  WrapError("handler error", fmt.Errorf("decode: %w", inner)),
+			expected: map[string]any{
+				"msg": "decode: validation failed field=email",
+				"cause": map[string]any{
+					"msg":   "validation failed",
+					"field": "email",
+				},
+			},
+		},
+		{
+			name: "fmt.Errorf around fmt.Errorf around serror",
+			err:  WrapError("handler error", fmt.Errorf("request: %w", fmt.Errorf("decode: %w", inner))),
+			expected: map[string]any{
+				"msg": "request: decode: validation failed field=email",
+				"cause": map[string]any{
+					"msg": "decode: validation failed field=email",
+					"cause": map[string]any{
+						"msg":   "validation failed",
+						"field": "email",
+					},
+				},
+			},
+		},
+		{
+			name:     "fmt.Errorf without serror stays flat",
+			err:      WrapError("handler error", fmt.Errorf("decode: %w", errors.New("eof"))),
+			expected: "decode: eof",
+		},
+		{
+			name: "errors.Join with serror",
+			err:  WrapError("handler error", errors.Join(errors.New("eof"), inner)),
+			expected: []any{
+				"eof",
+				map[string]any{
+					"msg":   "validation failed",
+					"field": "email",
+				},
+			},
+		},
+		{
+			name: "fmt.Errorf with several %w",
+			err:  WrapError("handler error", fmt.Errorf("decode: %w, %w", errors.New("eof"), inner)),
+			expected: map[string]any{
+				"msg": "decode: eof, validation failed field=email",
+				"causes": []any{
+					"eof",
+					map[string]any{
+						"msg":   "validation failed",
+						"field": "email",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+			logger.Error("operation failed", "error", tt.err)
+
+			var logOutput map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &logOutput); err != nil {
+				t.Fatalf("Failed to parse JSON output: %v", err)
+			}
+
+			errorGroup, ok := logOutput["error"].(map[string]any)
+			if !ok {
+				t.Fatalf("Expected 'error' to be a group, got %T", logOutput["error"])
+			}
+
+			if !reflect.DeepEqual(errorGroup["cause"], tt.expected) {
+				t.Errorf("cause = %#v, want %#v", errorGroup["cause"], tt.expected)
+			}
+		})
+	}
+}
+
+func TestWith(t *testing.T) {
+	original := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		err      error
+		attrs    []slog.Attr
+		expected string
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			attrs:    []slog.Attr{slog.String("request_id", "req-1")},
+			expected: "",
+		},
+		{
+			name:     "standard error",
+			err:      original,
+			attrs:    []slog.Attr{slog.String("request_id", "req-1")},
+			expected: "connection refused request_id=req-1",
+		},
+		{
+			name:     "serror merges attributes",
+			err:      WrapError("fetch failed", original, slog.String("request_id", "old"), slog.Int("retry", 1)),
+			attrs:    []slog.Attr{slog.String("request_id", "req-1"), slog.String("tenant", "acme")},
+			expected: "fetch failed cause=[connection refused] request_id=req-1 retry=1 tenant=acme",
+		},
+		{
+			name:     "multi-cause serror",
+			err:      WrapErrors("fetch failed", []error{original}),
+			attrs:    []slog.Attr{slog.String("request_id", "req-1")},
+			expected: "fetch failed causes=[[connection refused]] request_id=req-1",
+		},
+		{
+			name:     "fmt.Errorf",
+			err:      fmt.Errorf("fetch: %w", original),
+			attrs:    []slog.Attr{slog.String("request_id", "req-1")},
+			expected: "fetch: connection refused request_id=req-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := With(tt.err, tt.attrs...)
+			if tt.err == nil {
+				if err != nil {
+					t.Errorf("With(nil) = %v, want nil", err)
+				}
+				return
+			}
+
+			if err.Error() != tt.expected {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.expected)
+			}
+			if !errors.Is(err, original) {
+				t.Errorf("Expected errors.Is to find the original error")
+			}
+			if v, ok := LookupString(err, "request_id"); !ok || v != "req-1" {
+				t.Errorf("LookupString(request_id) = %q, %v", v, ok)
+			}
+		})
+	}
+}
+
+func TestWith_KeepsOriginal(t *testing.T) {
+	original := NewError("fetch failed", slog.String("request_id", "old"))
+	_ = With(original, slog.String("request_id", "new"))
+
+	if original.Error() != "fetch failed request_id=old" {
+		t.Errorf("With modified the original error: %q", original.Error())
+	}
+
+	definition := errTestNotFound.New()
+	if !errors.Is(With(definition, slog.String("k", "v")), errTestNotFound) {
+		t.Errorf("Expected errors.Is to match the definition after With")
+	}
+}
+
+func TestWith_Unwrap(t *testing.T) {
+	original := &customError{msg: "custom"}
+	err := With(original, slog.String("request_id", "req-1"))
+
+	var target *customError
+	if !errors.As(err, &target) || target != original {
+		t.Errorf("Expected errors.As to find the original error")
+	}
+
+	var serr Error
+	if !errors.As(err, &serr) {
+		t.Fatalf("Expected errors.As to find Error")
+	}
+	if serr.Message() != "custom" || serr.Cause() != original {
+		t.Errorf("Message() = %q, Cause() = %v", serr.Message(), serr.Cause())
+	}
+}
+
+func TestWith_LogValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected map[string]any
+	}{
+		{
+			name: "standard error",
+			err:  With(errors.New("connection refused"), slog.String("request_id", "req-1")),
+			expected: map[string]any{
+				"msg":        "connection refused",
+				"request_id": "req-1",
+			},
+		},
+		{
+			name: "fmt.Errorf around serror",
+			err: With(fmt.Errorf("fetch: %w", NewError("timeout", slog.Int("ms", 100))),
+				slog.String("request_id", "req-1")),
+			expected: map[string]any{
+				"msg": "fetch: timeout ms=100",
+				"cause": map[string]any{
+					"msg": "fetch: timeout ms=100",
+					"cause": map[string]any{
+						"msg": "timeout",
+						"ms":  float64(100),
+					},
+				},
+				"request_id": "req-1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+			logger.Error("operation failed", "error", tt.err)
+
+			var logOutput map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &logOutput); err != nil {
+				t.Fatalf("Failed to parse JSON output: %v", err)
+			}
+
+			if !reflect.DeepEqual(logOutput["error"], any(tt.expected)) {
+				t.Errorf("error = %#v, want %#v", logOutput["error"], tt.expected)
+			}
+		})
+	}
+}
+
+type customError struct {
+	msg string
+}
+
+func (c *customError) Error() string {
+	return c.msg
+}
+
+func annotatedLoad(fail bool) (err error) {
+	defer Annotate(&err, "load config", slog.String("path", "app.yaml"))
+
+	if fail {
+		return errors.New("file not found")
+	}
+
+	return nil
+}
+
+func TestAnnotate(t *testing.T) {
+	if err := annotatedLoad(false); err != nil {
+		t.Errorf("Annotate() changed a nil error to %v", err)
+	}
+
+	err := annotatedLoad(true)
+	if err == nil {
+		t.Fatalf("Annotate() lost the error")
+	}
+
+	expected := "load config cause=[file not found] path=app.yaml"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestAnnotate_Source(t *testing.T) {
+	previous := CaptureSource
+	CaptureSource = true
+	t.Cleanup(func() { CaptureSource = previous })
+
+	src := Source(annotatedLoad(true))
+	if src == nil {
+		t.Fatalf("Expected a source to be recorded")
+	}
+	if !strings.HasSuffix(src.Function, "annotatedLoad") {
+		t.Errorf("Source().Function = %q, want annotatedLoad", src.Function)
+	}
+}
+
+func TestNewAndWrap_KeyValues(t *testing.T) {
+	cause := errors.New("timeout")
+
+	tests := []struct {
+		name     string
+		create   func() error
+		expected string
+	}{
+		{
+			name: "New without arguments",
+			create: func() error {
+				return New("user not found")
+			},
+			expected: "user not found",
+		},
+		{
+			name: "New with key/value pairs",
+			create: func() error {
+				return New("user not found", "user_id", "123", "retry", 3)
+			},
+			expected: "user not found user_id=123 retry=3",
+		},
+		{
+			name: "New with mixed attributes",
+			create: func() error {
+				return New("user not found", slog.String("user_id", "123"), "table", "users")
+			},
+			expected: "user not found user_id=123 table=users",
+		},
+		{
+			name: "New with missing value",
+			create: func() error {
+				return New("user not found", "user_id", "123", "dangling")
+			},
+			expected: "user not found user_id=123 !BADKEY=dangling",
+		},
+		{
+			name: "New with non-string key",
+			create: func() error {
+				return New("user not found", 42, "user_id", "123")
+			},
+			expected: "user not found !BADKEY=42 user_id=123",
+		},
+		{
+			name: "Wrap with key/value pairs",
+			create: func() error {
+				return Wrap("fetch failed", cause, "url", "/users", "status", 503)
+			},
+			expected: "fetch failed cause=[timeout] url=/users status=503",
+		},
+		{
+			name: "New with options",
+			create: func() error {
+				return New("user not found", Code("user.not_found"), "user_id", "123")
+			},
+			expected: "user not found user_id=123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.create().Error(); actual != tt.expected {
+				t.Errorf("Error() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+
+	if v, ok := LookupInt64(Wrap("fetch failed", cause, "status", 503), "status"); !ok || v != 503 {
+		t.Errorf("LookupInt64(status) = %d, %v", v, ok)
+	}
+	if CodeOf(New("user not found", Code("user.not_found"))) != "user.not_found" {
+		t.Errorf("New lost the code option")
+	}
+}
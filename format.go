@@ -0,0 +1,103 @@
+package serrors
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Format implements fmt.Formatter. The %s and %v verbs print the output of
+// Error, and %q prints it quoted. The %+v verb prints the whole chain as an
+// indented tree, one level per wrapped error, with its attributes and
+// captured stack on separate lines.
+func (s serror) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			var b strings.Builder
+			writeTree(&b, s, 0, "")
+			_, _ = io.WriteString(f, strings.TrimSuffix(b.String(), "\n"))
+
+			return
+		}
+
+		_, _ = io.WriteString(f, s.Error())
+	case 's':
+		_, _ = io.WriteString(f, s.Error())
+	case 'q':
+		_, _ = io.WriteString(f, strconv.Quote(s.Error()))
+	default:
+		_, _ = fmt.Fprintf(f, "%%!%c(%s)", verb, s.Error())
+	}
+}
+
+// Format implements fmt.Formatter, like the errors created from d.
+func (d *Definition) Format(f fmt.State, verb rune) {
+	d.s.Format(f, verb)
+}
+
+// writeTree writes err and its causes to b, at the given indentation depth.
+// The first line is prefixed with label, if any.
+func writeTree(b *strings.Builder, err error, depth int, label string) {
+	indent := strings.Repeat("  ", depth)
+	detail := indent + "  "
+
+	s, ok := asSerror(err)
+	if !ok {
+		writeLines(b, indent, label, err.Error())
+		writeCauses(b, unwrapAll(err), depth+1)
+
+		return
+	}
+
+	writeLines(b, indent, label, s.Message())
+
+	if s.code != "" {
+		_, _ = b.WriteString(detail + CodeKey + "=" + s.code + "\n")
+	}
+
+	for _, attr := range s.attrs {
+		_, _ = b.WriteString(detail + attr.String() + "\n")
+	}
+
+	if s.hasSource() {
+		_, _ = b.WriteString(detail + slog.SourceKey + "=" + sourceString(s.Source()) + "\n")
+	}
+
+	if s.stack != nil {
+		_, _ = b.WriteString(detail + StackKey + ":\n")
+
+		for _, frame := range s.stack.strings() {
+			_, _ = b.WriteString(detail + "  " + frame + "\n")
+		}
+	}
+
+	writeCauses(b, s.Causes(), depth+1)
+}
+
+func writeCauses(b *strings.Builder, causes []error, depth int) {
+	for i, cause := range causes {
+		label := CauseKey + ": "
+		if len(causes) > 1 {
+			label = CauseKey + "[" + strconv.Itoa(i) + "]: "
+		}
+
+		writeTree(b, cause, depth, label)
+	}
+}
+
+// writeLines writes the label followed by text, aligning the continuation
+// lines of multi-line text.
+func writeLines(b *strings.Builder, indent, label, text string) {
+	continuation := indent + strings.Repeat(" ", len(label))
+
+	for i, line := range strings.Split(text, "\n") {
+		if i == 0 {
+			_, _ = b.WriteString(indent + label + line + "\n")
+		} else {
+			_, _ = b.WriteString(continuation + line + "\n")
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSerror_Format(t *testing.T) {
+	chain := WrapError("handler error",
+		WrapError("request processing failed",
+			NewError("validation failed", slog.String("field", "email")),
+			slog.String("request_id", "req-123")),
+		Code("handler"),
+		slog.String("handler", "UserHandler"))
+
+	tests := []struct {
+		name     string
+		format   string
+		err      error
+		expected string
+	}{
+		{
+			name:     "%s",
+			format:   "%s",
+			err:      chain,
+			expected: chain.Error(),
+		},
+		{
+			name:     "%v",
+			format:   "%v",
+			err:      chain,
+			expected: chain.Error(),
+		},
+		{
+			name:     "%q",
+			format:   "%q",
+			err:      NewError(`say "hi"`),
+			expected: `"say \"hi\""`,
+		},
+		{
+			name:   "%+v chain",
+			format: "%+v",
+			err:    chain,
+			expected: `handler error
+  code=handler
+  handler=UserHandler
+  cause: request processing failed
+    request_id=req-123
+    cause: validation failed
+      field=email`,
+		},
+		{
+			name:   "%+v through fmt.Errorf and multiple causes",
+			format: "%+v",
+			err: WrapError("handler error", fmt.Errorf("decode: %w",
+				WrapErrors("batch failed", []error{
+					errors.New("a failed"),
+					NewError("b failed", slog.Int("row", 2)),
+				}))),
+			expected: `handler error
+  cause: decode: batch failed causes=[[a failed] [b failed row=2]]
+    cause: batch failed
+      cause[0]: a failed
+      cause[1]: b failed
+        row=2`,
+		},
+		{
+			name:   "%+v multi-line message",
+			format: "%+v",
+			err:    WrapError("outer", errors.New("line1\nline2")),
+			expected: `outer
+  cause: line1
+         line2`,
+		},
+		{
+			name:     "%+v definition",
+			format:   "%+v",
+			err:      errTestNotFound,
+			expected: "not found\n  code=test.definition.not_found\n  kind=lookup",
+		},
+		{
+			name:     "%+v wrapped in fmt.Errorf uses Error",
+			format:   "%+v",
+			err:      fmt.Errorf("outer: %w", NewError("inner", slog.String("k", "v"))),
+			expected: "outer: inner k=v",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := fmt.Sprintf(tt.format, tt.err); actual != tt.expected {
+				t.Errorf("Sprintf(%q) =\n%s\nwant\n%s", tt.format, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSerror_Format_Stack(t *testing.T) {
+	setStackCapture(t, StackInnermost)
+
+	err := WrapError("outer", NewError("inner"))
+	actual := fmt.Sprintf("%+v", err)
+
+	lines := strings.Split(actual, "\n")
+	if len(lines) < 4 {
+		t.Fatalf("Expected a stack in the output:\n%s", actual)
+	}
+	if lines[0] != "outer" || lines[1] != "  cause: inner" || lines[2] != "    stack:" {
+		t.Errorf("Unexpected output:\n%s", actual)
+	}
+	if !strings.Contains(lines[3], "TestSerror_Format_Stack") {
+		t.Errorf("Expected the first frame in TestSerror_Format_Stack, got %q", lines[3])
+	}
+}
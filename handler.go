@@ -0,0 +1,268 @@
+package serrors
+
+import (
+	"context"
+	"go/token"
+	"log/slog"
+	"reflect"
+	"slices"
+)
+
+// KindKey is the key under which [Handler] logs the kind of an error.
+var KindKey = "kind"
+
+// HandlerOptions are options for a [Handler].
+type HandlerOptions struct {
+	// HoistKeys lists the keys of attributes that are copied from the chain
+	// of a logged error to the record itself, such as "request_id".
+	HoistKeys []string
+	// AddKind adds the kind of a logged error under KindKey. The kind is the
+	// code of the error if it has one, and otherwise the type of the
+	// innermost error of its chain whose type is exported, such as
+	// *fs.PathError. No kind is added if there is neither.
+	AddKind bool
+	// Mode selects how the chain of a logged error is rendered. The zero
+	// value uses DefaultLogMode.
+	Mode LogMode
+	// AddStack adds the innermost stack trace of the chain of a logged error
+	// under StackKey, unless the outermost error already logs one, so that
+	// it can always be found at the same place.
+	AddStack bool
+}
+
+// Handler is a [slog.Handler] that expands every error attribute of a
+// record, at any key, into its structured form before passing the record
+// to the next handler.
+type Handler struct {
+	next slog.Handler
+	opts HandlerOptions
+	// groups are the groups opened with WithGroup, outermost first. They are
+	// applied to records here rather than by next, so that hoisted
+	// attributes stay at the top level.
+	groups []group
+}
+
+// group is a group opened with WithGroup, along with the attributes added
+// to it.
+type group struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// NewHandler creates a [Handler] that passes records to next. If opts is
+// nil, the default options are used.
+func NewHandler(next slog.Handler, opts *HandlerOptions) *Handler {
+	h := &Handler{next: next}
+	if opts != nil {
+		h.opts = *opts
+	}
+
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	record := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+
+	attrs, hoisted := h.process(attrs)
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		value := slog.GroupValue(slices.Concat(h.groups[i].attrs, attrs)...)
+		attrs = []slog.Attr{{Key: h.groups[i].name, Value: value}}
+	}
+
+	record.AddAttrs(appendHoisted(attrs, hoisted)...)
+
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, hoisted := h.process(attrs)
+
+	if len(h.groups) == 0 {
+		return &Handler{next: h.next.WithAttrs(appendHoisted(attrs, hoisted)), opts: h.opts}
+	}
+
+	groups := slices.Clone(h.groups)
+	last := &groups[len(groups)-1]
+	last.attrs = slices.Concat(last.attrs, attrs)
+
+	next := h.next
+	if len(hoisted) > 0 {
+		next = next.WithAttrs(hoisted)
+	}
+
+	return &Handler{next: next, opts: h.opts, groups: groups}
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := append(slices.Clip(h.groups), group{name: name})
+
+	return &Handler{next: h.next, opts: h.opts, groups: groups}
+}
+
+// process expands the errors in attrs, and returns the attributes hoisted
+// from them.
+func (h *Handler) process(attrs []slog.Attr) ([]slog.Attr, []slog.Attr) {
+	var hoisted []slog.Attr
+
+	processed := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		processed = append(processed, h.expand(attr, &hoisted))
+	}
+
+	return processed, hoisted
+}
+
+// appendHoisted appends the hoisted attributes whose key is not already in
+// attrs.
+func appendHoisted(attrs, hoisted []slog.Attr) []slog.Attr {
+	for _, attr := range hoisted {
+		exists := slices.ContainsFunc(attrs, func(a slog.Attr) bool {
+			return a.Key == attr.Key
+		})
+
+		if !exists {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	return attrs
+}
+
+func (h *Handler) expand(attr slog.Attr, hoisted *[]slog.Attr) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+
+		for i, a := range group {
+			expanded[i] = h.expand(a, hoisted)
+		}
+
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(expanded...)}
+	case slog.KindAny, slog.KindLogValuer:
+		err, ok := attr.Value.Any().(error)
+		if !ok {
+			return attr
+		}
+
+		h.hoist(err, hoisted)
+
+		return slog.Attr{Key: attr.Key, Value: h.errorValue(err)}
+	default:
+		return attr
+	}
+}
+
+func (h *Handler) hoist(err error, hoisted *[]slog.Attr) {
+	for _, key := range h.opts.HoistKeys {
+		exists := slices.ContainsFunc(*hoisted, func(a slog.Attr) bool {
+			return a.Key == key
+		})
+		if exists {
+			continue
+		}
+
+		if value, ok := Lookup(err, key); ok {
+			*hoisted = append(*hoisted, slog.Attr{Key: key, Value: value})
+		}
+	}
+}
+
+// errorValue returns the structured value of err, with the derived fields
+// requested by the options.
+func (h *Handler) errorValue(err error) slog.Value {
+	var value slog.Value
+	if h.opts.Mode.resolve() == LogFlat && hasLogValuer(err) {
+		value = flatValue(err)
+	} else {
+		value = causeValue(err).Resolve()
+	}
+
+	var derived []slog.Attr
+
+	if h.opts.AddKind {
+		if kind := errorKind(err); kind != "" {
+			derived = append(derived, slog.String(KindKey, kind))
+		}
+	}
+
+	if h.opts.AddStack && !hasKey(value, StackKey) {
+		if frames := StackTrace(err); frames != nil {
+			derived = append(derived, slog.Any(StackKey, frameStrings(frames)))
+		}
+	}
+
+	if len(derived) == 0 {
+		return value
+	}
+
+	if value.Kind() != slog.KindGroup {
+		value = slog.GroupValue(slog.String(slog.MessageKey, err.Error()))
+	}
+
+	return slog.GroupValue(append(slices.Clip(value.Group()), derived...)...)
+}
+
+// hasKey reports whether value is a group containing key.
+func hasKey(value slog.Value, key string) bool {
+	if value.Kind() != slog.KindGroup {
+		return false
+	}
+
+	return slices.ContainsFunc(value.Group(), func(a slog.Attr) bool {
+		return a.Key == key
+	})
+}
+
+// errorKind returns the code of err, or the type of the innermost error of
+// its chain whose type is exported, following the first branch of
+// multi-cause errors.
+func errorKind(err error) string {
+	if code := CodeOf(err); code != "" {
+		return code
+	}
+
+	var kind string
+	for err != nil {
+		if t := reflect.TypeOf(err); exportedType(t) {
+			kind = t.String()
+		}
+
+		causes := unwrapAll(err)
+		if len(causes) == 0 {
+			break
+		}
+
+		err = causes[0]
+	}
+
+	return kind
+}
+
+// exportedType reports whether t, or the type it points to, is a named type
+// exported by its package.
+func exportedType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.PkgPath() != "" && token.IsExported(t.Name())
+}
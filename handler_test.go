@@ -0,0 +1,255 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func logWithHandler(t *testing.T, opts *HandlerOptions, log func(*slog.Logger)) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), opts))
+
+	log(logger)
+
+	var logOutput map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &logOutput); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	return logOutput
+}
+
+func TestHandler_ExpandsErrors(t *testing.T) {
+	inner := NewError("validation failed", slog.String("field", "email"))
+
+	tests := []struct {
+		name     string
+		log      func(*slog.Logger)
+		key      string
+		expected any
+	}{
+		{
+			name: "serror at any key",
+			log: func(l *slog.Logger) {
+				l.Error("failed", "failure", inner)
+			},
+			key: "failure",
+			expected: map[string]any{
+				"msg":   "validation failed",
+				"field": "email",
+			},
+		},
+		{
+			name: "serror below fmt.Errorf",
+			log: func(l *slog.Logger) {
+				l.Error("failed", "err", fmt.Errorf("decode: %w", inner))
+			},
+			key: "err",
+			expected: map[string]any{
+				"msg": "decode: validation failed field=email",
+				"cause": map[string]any{
+					"msg":   "validation failed",
+					"field": "email",
+				},
+			},
+		},
+		{
+			name: "error inside a group",
+			log: func(l *slog.Logger) {
+				l.Error("failed", slog.Group("request", slog.Any("error", fmt.Errorf("decode: %w", inner))))
+			},
+			key: "request",
+			expected: map[string]any{
+				"error": map[string]any{
+					"msg": "decode: validation failed field=email",
+					"cause": map[string]any{
+						"msg":   "validation failed",
+						"field": "email",
+					},
+				},
+			},
+		},
+		{
+			name: "standard error stays a string",
+			log: func(l *slog.Logger) {
+				l.Error("failed", "error", errors.New("eof"))
+			},
+			key:      "error",
+			expected: "eof",
+		},
+		{
+			name: "error passed to With",
+			log: func(l *slog.Logger) {
+				l.With("error", fmt.Errorf("decode: %w", inner)).Error("failed")
+			},
+			key: "error",
+			expected: map[string]any{
+				"msg": "decode: validation failed field=email",
+				"cause": map[string]any{
+					"msg":   "validation failed",
+					"field": "email",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logOutput := logWithHandler(t, nil, tt.log)
+
+			if !reflect.DeepEqual(logOutput[tt.key], tt.expected) {
+				t.Errorf("%s = %#v, want %#v", tt.key, logOutput[tt.key], tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandler_Hoist(t *testing.T) {
+	err := fmt.Errorf("handler: %w", WrapError("fetch failed",
+		NewError("timeout", slog.String("request_id", "req-inner"), slog.String("tenant", "acme")),
+		slog.String("request_id", "req-1")))
+
+	logOutput := logWithHandler(t, &HandlerOptions{HoistKeys: []string{"request_id", "tenant", "missing"}},
+		func(l *slog.Logger) {
+			l.Error("failed", "error", err, "tenant", "explicit")
+		})
+
+	if logOutput["request_id"] != "req-1" {
+		t.Errorf("request_id = %v, want req-1", logOutput["request_id"])
+	}
+	if logOutput["tenant"] != "explicit" {
+		t.Errorf("tenant = %v, want the explicit record attribute", logOutput["tenant"])
+	}
+	if _, exists := logOutput["missing"]; exists {
+		t.Errorf("Unexpected hoisted key 'missing'")
+	}
+}
+
+func TestHandler_Hoist_WithGroup(t *testing.T) {
+	err := WrapError("fetch failed", NewError("timeout"), slog.String("request_id", "r1"))
+
+	logOutput := logWithHandler(t, &HandlerOptions{HoistKeys: []string{"request_id"}},
+		func(l *slog.Logger) {
+			l.WithGroup("g").With("attempt", 2).WithGroup("h").Error("failed", "err", err)
+		})
+
+	if logOutput["request_id"] != "r1" {
+		t.Errorf("request_id = %v, want r1 at the top level", logOutput["request_id"])
+	}
+
+	g, ok := logOutput["g"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected 'g' to be a group, got %v", logOutput["g"])
+	}
+	if _, exists := g["request_id"]; exists {
+		t.Errorf("Unexpected hoisted key inside the group: %v", g)
+	}
+	if g["attempt"] != float64(2) {
+		t.Errorf("g.attempt = %v, want 2", g["attempt"])
+	}
+
+	h, ok := g["h"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected 'g.h' to be a group, got %v", g["h"])
+	}
+	if errGroup, ok := h["err"].(map[string]any); !ok || errGroup["msg"] != "fetch failed" {
+		t.Errorf("g.h.err = %v, want the expanded error", h["err"])
+	}
+
+	logOutput = logWithHandler(t, &HandlerOptions{HoistKeys: []string{"request_id"}},
+		func(l *slog.Logger) {
+			l.WithGroup("g").With("err", err).Info("started")
+		})
+
+	if logOutput["request_id"] != "r1" {
+		t.Errorf("request_id = %v, want r1 at the top level for attributes added to the group", logOutput["request_id"])
+	}
+}
+
+func TestHandler_DerivedFields(t *testing.T) {
+	setStackCapture(t, StackInnermost)
+
+	tests := []struct {
+		name          string
+		err           error
+		expectedKind  string
+		expectedStack bool
+	}{
+		{
+			name:          "innermost exported type",
+			err:           WrapError("read failed", &fs.PathError{Op: "open", Path: "config.json", Err: fs.ErrNotExist}),
+			expectedKind:  "*fs.PathError",
+			expectedStack: true,
+		},
+		{
+			name:          "unexported root cause type",
+			err:           WrapError("read failed", &customError{msg: "custom"}),
+			expectedKind:  "",
+			expectedStack: true,
+		},
+		{
+			name:          "code",
+			err:           WrapError("handler", NewError("user not found", Code("user.not_found"))),
+			expectedKind:  "user.not_found",
+			expectedStack: true,
+		},
+		{
+			name:          "stack of a cause below fmt.Errorf",
+			err:           fmt.Errorf("handler: %w", NewError("user not found")),
+			expectedKind:  "",
+			expectedStack: true,
+		},
+		{
+			name:          "standard error",
+			err:           errors.New("eof"),
+			expectedKind:  "",
+			expectedStack: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logOutput := logWithHandler(t, &HandlerOptions{AddKind: true, AddStack: true},
+				func(l *slog.Logger) {
+					l.Error("failed", "error", tt.err)
+				})
+
+			errorGroup, ok := logOutput["error"].(map[string]any)
+			if !ok && tt.expectedKind == "" && !tt.expectedStack {
+				// Nothing was derived, so the error is logged as is.
+				return
+			}
+			if !ok {
+				t.Fatalf("Expected 'error' to be a group, got %T", logOutput["error"])
+			}
+
+			kind, exists := errorGroup[KindKey]
+			if tt.expectedKind == "" && exists {
+				t.Errorf("Unexpected kind %v", kind)
+			}
+			if tt.expectedKind != "" && kind != tt.expectedKind {
+				t.Errorf("kind = %v, want %v", kind, tt.expectedKind)
+			}
+
+			if errorGroup["msg"] == nil {
+				t.Errorf("Expected the message to be logged")
+			}
+
+			stack, ok := errorGroup[StackKey].([]any)
+			if tt.expectedStack && (!ok || len(stack) == 0) {
+				t.Errorf("Expected a stack, got %v", errorGroup[StackKey])
+			}
+			if !tt.expectedStack && errorGroup[StackKey] != nil {
+				t.Errorf("Unexpected stack %v", errorGroup[StackKey])
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// HTTPKey is the key of the group holding the request and response
+	// details of errors created by [FromResponse] and [FromTransportError].
+	HTTPKey = "http"
+	// BodySnippetSize is the maximum number of bytes of a response body
+	// recorded by [FromResponse].
+	BodySnippetSize = 512
+)
+
+// maxErrorBodySize is the maximum number of bytes of a response body read
+// to decode the error it contains.
+const maxErrorBodySize = 64 << 10
+
+// redacted replaces the values of query parameters in recorded URLs.
+const redacted = "REDACTED"
+
+// FromResponse returns an error describing resp, or nil if it succeeded.
+// A response fails if its status is not 2xx, or if it is a JSON-RPC error
+// object, which is usually sent with a 200 status. The error holds the
+// status, the method, the URL with its query values and password redacted,
+// and a snippet of the body, in a group under HTTPKey. A problem+json body
+// is decoded with [FromProblem], and a JSON-RPC error object into an error
+// with its message, and either becomes the cause.
+//
+// The body of failed and JSON responses is read partially, and resp.Body is
+// replaced so that it can still be read in full. The caller remains
+// responsible for closing it.
+func FromResponse(resp *http.Response) error {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	success := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if success && mediaType != "application/json" {
+		return nil
+	}
+
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+	var cause error
+	if readErr == nil {
+		cause = responseCause(mediaType, body)
+	}
+
+	msg := "unexpected response status"
+	if success {
+		if cause == nil {
+			return nil
+		}
+
+		msg = "JSON-RPC error response"
+	}
+
+	attrs := []any{slog.Int("status", resp.StatusCode)}
+	if req := resp.Request; req != nil {
+		attrs = append(attrs, slog.String("method", req.Method))
+		if req.URL != nil {
+			attrs = append(attrs, slog.String("url", redactURL(req.URL)))
+		}
+	}
+
+	if snippet := bodySnippet(body); snippet != "" {
+		attrs = append(attrs, slog.String("body", snippet))
+	}
+
+	s := serror{msg: msg, err: cause, attrs: []slog.Attr{slog.Group(HTTPKey, attrs...)}}
+
+	return newError(1, s)
+}
+
+// FromTransportError wraps an error returned by an [http.Client]. When err
+// is a [*url.Error], the method and redacted URL of the request are recorded
+// in a group under HTTPKey, and the URL in err itself is redacted as well.
+// FromTransportError returns nil if err is nil.
+func FromTransportError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	s := serror{msg: "http request failed", err: err}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		attrs := []any{slog.String("method", strings.ToUpper(urlErr.Op))}
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			attrs = append(attrs, slog.String("url", redactURL(u)))
+
+			if err == error(urlErr) {
+				s.err = &url.Error{Op: urlErr.Op, URL: redactURL(u), Err: urlErr.Err}
+			}
+		}
+
+		s.attrs = []slog.Attr{slog.Group(HTTPKey, attrs...)}
+	}
+
+	return newError(1, s)
+}
+
+// responseCause decodes the error contained in a response body of the given
+// media type, or returns nil.
+func responseCause(mediaType string, body []byte) error {
+	switch mediaType {
+	case ProblemContentType:
+		if err, decodeErr := FromProblem(body); decodeErr == nil {
+			return err
+		}
+	case "application/json":
+		return rpcError(body)
+	}
+
+	return nil
+}
+
+// rpcError decodes the JSON-RPC error object in body, or returns nil.
+func rpcError(body []byte) error {
+	var rpc struct {
+		JSONRPC string `json:"jsonrpc"`
+		Error   *struct {
+			Code    int64           `json:"code"`
+			Message string          `json:"message"`
+			Data    json.RawMessage `json:"data"`
+		} `json:"error"`
+	}
+
+	if json.Unmarshal(body, &rpc) != nil || rpc.JSONRPC == "" || rpc.Error == nil {
+		return nil
+	}
+
+	s := serror{msg: rpc.Error.Message, attrs: []slog.Attr{slog.Int64("rpc_code", rpc.Error.Code)}}
+	if len(rpc.Error.Data) > 0 {
+		if data, err := decodeValue(rpc.Error.Data, "", nil); err == nil {
+			s.attrs = append(s.attrs, slog.Attr{Key: "rpc_data", Value: data})
+		}
+	}
+
+	return s
+}
+
+// redactURL returns u with its password and query values redacted.
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Redacted()
+	}
+
+	query := u.Query()
+	for key, values := range query {
+		for i := range values {
+			values[i] = redacted
+		}
+		query[key] = values
+	}
+
+	c := *u
+	c.RawQuery = query.Encode()
+
+	return c.Redacted()
+}
+
+// bodySnippet returns at most BodySnippetSize bytes of body as valid UTF-8,
+// with an ellipsis if it was truncated.
+func bodySnippet(body []byte) string {
+	if len(body) <= BodySnippetSize {
+		return strings.ToValidUTF8(string(body), "�")
+	}
+
+	cut := BodySnippetSize
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+
+	return strings.ToValidUTF8(string(body[:cut]), "�") + "…"
+}
@@ -0,0 +1,171 @@
+package serrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// PanicKey is the key under which the error recovered from a panic by
+// [ErrorHandler] records the panic value.
+var PanicKey = "panic"
+
+// ErrorHandler is an [http.Handler] that calls a handler returning an error,
+// and renders and logs that error. Use [HTTPHandler] to create one.
+type ErrorHandler struct {
+	fn func(http.ResponseWriter, *http.Request) error
+
+	// Logger receives the returned errors. If nil, [slog.Default] is used.
+	Logger *slog.Logger
+	// ExtensionKeys lists the keys of attributes rendered as extension
+	// members of problem details, as with [Problem].
+	ExtensionKeys []string
+}
+
+// HTTPHandler adapts fn to an [http.Handler]. When fn returns an error, its
+// status is derived as with [Problem], and the response is problem details
+// if the request accepts JSON, or the plain detail otherwise. For server
+// errors, the detail is replaced with the status text and the extension
+// members are dropped, so that internal messages are not exposed. Nothing
+// is written if fn already wrote the header.
+//
+// The error is logged once, along with the method, route and remote address
+// of the request. A panic in fn is recovered into an error carrying a stack
+// trace, regardless of StackCapture, and handled the same way, except for
+// [http.ErrAbortHandler].
+func HTTPHandler(fn func(http.ResponseWriter, *http.Request) error) *ErrorHandler {
+	return &ErrorHandler{fn: fn}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *ErrorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw := &responseWriter{ResponseWriter: w}
+
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+
+		if v == http.ErrAbortHandler {
+			panic(v)
+		}
+
+		h.handle(rw, r, panicError(v))
+	}()
+
+	if err := h.fn(rw, r); err != nil {
+		h.handle(rw, r, err)
+	}
+}
+
+func (h *ErrorHandler) handle(w *responseWriter, r *http.Request, err error) {
+	p := Problem(err, h.ExtensionKeys...)
+	if p.Status >= http.StatusInternalServerError {
+		p.Detail = http.StatusText(p.Status)
+		p.Extensions = nil
+	}
+
+	h.log(r, err, p.Status)
+
+	if w.written {
+		return
+	}
+
+	if !acceptsJSON(r) {
+		http.Error(w, p.Detail, p.Status)
+		return
+	}
+
+	body, marshalErr := json.Marshal(p)
+	if marshalErr != nil {
+		status := http.StatusInternalServerError
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(body)
+}
+
+func (h *ErrorHandler) log(r *http.Request, err error, status int) {
+	logger := h.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	level := slog.LevelError
+	if status < http.StatusInternalServerError {
+		level = slog.LevelWarn
+	}
+
+	logger.LogAttrs(r.Context(), level, "http handler error",
+		slog.Any("error", err),
+		slog.Int(StatusKey, status),
+		slog.String("method", r.Method),
+		slog.String("route", r.Pattern),
+		slog.String("remote_addr", r.RemoteAddr),
+	)
+}
+
+// panicError returns the error recovered from a panic with value v. It must
+// be called by the deferred function that recovered v.
+func panicError(v any) error {
+	s := serror{msg: "panic", attrs: []slog.Attr{slog.String(PanicKey, fmt.Sprint(v))}}
+	if err, ok := v.(error); ok {
+		s.err = err
+		s.attrs = nil
+	}
+
+	s = newError(1, s)
+	if s.stack == nil {
+		s.stack = captureStack(1)
+	}
+
+	return s
+}
+
+// acceptsJSON reports whether the Accept header of r lists a JSON media
+// type.
+func acceptsJSON(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for part := range strings.SplitSeq(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(part)
+			if err != nil {
+				continue
+			}
+
+			if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// responseWriter records whether the header of a response was written.
+type responseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped writer, for use by [http.ResponseController].
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
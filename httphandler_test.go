@@ -0,0 +1,242 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveWithHandler(t *testing.T, accept string, fn func(http.ResponseWriter, *http.Request) error) (*httptest.ResponseRecorder, []map[string]any) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	h := HTTPHandler(fn)
+	h.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	h.ExtensionKeys = []string{"user_id"}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /users/{id}", h)
+
+	r := httptest.NewRequest(http.MethodGet, "/users/123", nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	var records []map[string]any
+	for line := range bytes.Lines(buf.Bytes()) {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("Failed to parse JSON output: %v", err)
+		}
+
+		records = append(records, record)
+	}
+
+	return w, records
+}
+
+func TestHTTPHandler(t *testing.T) {
+	notFound := func(http.ResponseWriter, *http.Request) error {
+		return NewError("user not found", Code(testCodeNotFound),
+			slog.Int(StatusKey, http.StatusNotFound), slog.String("user_id", "123"))
+	}
+
+	tests := []struct {
+		name        string
+		accept      string
+		fn          func(http.ResponseWriter, *http.Request) error
+		status      int
+		contentType string
+		body        string
+		level       string
+	}{
+		{
+			name:        "problem details",
+			accept:      "application/json, text/plain;q=0.5",
+			fn:          notFound,
+			status:      http.StatusNotFound,
+			contentType: ProblemContentType,
+			body:        `{"type":"urn:problem-type:test.not_found","title":"The resource does not exist","status":404,"detail":"user not found","code":"test.not_found","user_id":"123"}`,
+			level:       "WARN",
+		},
+		{
+			name:        "plain text",
+			accept:      "text/html",
+			fn:          notFound,
+			status:      http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8",
+			body:        "user not found\n",
+			level:       "WARN",
+		},
+		{
+			name:   "server error hides detail",
+			accept: ProblemContentType,
+			fn: func(http.ResponseWriter, *http.Request) error {
+				return NewError("connect to db at 10.0.0.1 failed", slog.String("user_id", "123"))
+			},
+			status:      http.StatusInternalServerError,
+			contentType: ProblemContentType,
+			body:        `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Internal Server Error"}`,
+			level:       "ERROR",
+		},
+		{
+			name:   "out of range status",
+			accept: ProblemContentType,
+			fn: func(http.ResponseWriter, *http.Request) error {
+				return NewError("order failed", slog.Int(StatusKey, 3))
+			},
+			status:      http.StatusInternalServerError,
+			contentType: ProblemContentType,
+			body:        `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Internal Server Error"}`,
+			level:       "ERROR",
+		},
+		{
+			name: "out of range status in plain text",
+			fn: func(http.ResponseWriter, *http.Request) error {
+				return NewError("order failed", slog.Int(StatusKey, 3))
+			},
+			status:      http.StatusInternalServerError,
+			contentType: "text/plain; charset=utf-8",
+			body:        "Internal Server Error\n",
+			level:       "ERROR",
+		},
+		{
+			name: "header already written",
+			fn: func(w http.ResponseWriter, _ *http.Request) error {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte("partial"))
+
+				return errors.New("stream interrupted")
+			},
+			status: http.StatusAccepted,
+			body:   "partial",
+			level:  "ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, records := serveWithHandler(t, tt.accept, tt.fn)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+
+			if actual := w.Header().Get("Content-Type"); actual != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", actual, tt.contentType)
+			}
+
+			if actual := w.Body.String(); actual != tt.body {
+				t.Errorf("body = %s, want %s", actual, tt.body)
+			}
+
+			if len(records) != 1 {
+				t.Fatalf("logged %d records, want 1", len(records))
+			}
+
+			record := records[0]
+			if record["level"] != tt.level {
+				t.Errorf("level = %v, want %s", record["level"], tt.level)
+			}
+
+			if record["method"] != http.MethodGet || record["route"] != "GET /users/{id}" || record["remote_addr"] != "192.0.2.1:1234" {
+				t.Errorf("request attributes = %v", record)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_Success(t *testing.T) {
+	w, records := serveWithHandler(t, "", func(w http.ResponseWriter, _ *http.Request) error {
+		_, _ = w.Write([]byte("ok"))
+		return nil
+	})
+
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Errorf("response = %d %q, want 200 \"ok\"", w.Code, w.Body.String())
+	}
+
+	if len(records) != 0 {
+		t.Errorf("logged %d records, want 0", len(records))
+	}
+}
+
+func TestHTTPHandler_Panic(t *testing.T) {
+	w, records := serveWithHandler(t, "", func(http.ResponseWriter, *http.Request) error {
+		panic("nil map write")
+	})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("logged %d records, want 1", len(records))
+	}
+
+	logged, ok := records[0]["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("error = %v, want a group", records[0]["error"])
+	}
+
+	if logged[PanicKey] != "nil map write" {
+		t.Errorf("panic = %v, want nil map write", logged[PanicKey])
+	}
+
+	stack, ok := logged[StackKey].([]any)
+	if !ok || len(stack) == 0 {
+		t.Fatalf("stack = %v, want frames", logged[StackKey])
+	}
+
+	found := false
+	for _, frame := range stack {
+		if strings.Contains(frame.(string), "TestHTTPHandler_Panic") {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("stack = %v, want the panicking function", stack)
+	}
+}
+
+func TestHTTPHandler_PanicWithError(t *testing.T) {
+	_, records := serveWithHandler(t, "", func(http.ResponseWriter, *http.Request) error {
+		panic(errors.New("boom"))
+	})
+
+	if len(records) != 1 {
+		t.Fatalf("logged %d records, want 1", len(records))
+	}
+
+	logged, ok := records[0]["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("error = %v, want a group", records[0]["error"])
+	}
+
+	if logged[CauseKey] != "boom" {
+		t.Errorf("cause = %v, want boom", logged[CauseKey])
+	}
+}
+
+func TestHTTPHandler_AbortHandler(t *testing.T) {
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recover() = %v, want http.ErrAbortHandler", v)
+		}
+	}()
+
+	h := HTTPHandler(func(http.ResponseWriter, *http.Request) error {
+		panic(http.ErrAbortHandler)
+	})
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,413 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// AttrsKey is the key under which the JSON encoding of an error nests its
+	// attributes, so that they cannot be confused with its other members.
+	AttrsKey = "attrs"
+	// AttrKindsKey is the key under which the JSON encoding of an error
+	// records the [slog.Kind] of attributes that JSON cannot represent
+	// faithfully, such as int64, durations and times, keyed by their dotted
+	// path.
+	AttrKindsKey = "attr_kinds"
+)
+
+// inlineKey marks encoded errors whose message already contains the text of
+// their causes.
+const inlineKey = "inline"
+
+// MarshalJSON implements json.Marshaler, using the shape produced by
+// LogValue in LogNested mode, except that the attributes are nested under
+// AttrsKey.
+func (s serror) MarshalJSON() ([]byte, error) {
+	return appendJSONError(nil, s, true), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d *Definition) MarshalJSON() ([]byte, error) {
+	return d.s.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Causes that were plain errors
+// are restored as plain errors with the same message, and standard wrappers
+// as structured errors. Members outside of AttrsKey that are not part of the
+// shape, such as those found in the output of [slog.JSONHandler], are
+// restored as attributes too.
+func (s *serror) UnmarshalJSON(data []byte) error {
+	members, err := decodeObject(data)
+	if err != nil {
+		return err
+	}
+
+	kinds := map[string]string{}
+	for _, m := range members {
+		if m.key == AttrKindsKey {
+			if err := json.Unmarshal(m.value, &kinds); err != nil {
+				return err
+			}
+		}
+	}
+
+	*s = serror{}
+
+	for _, m := range members {
+		var err error
+
+		switch m.key {
+		case AttrKindsKey:
+		case slog.MessageKey:
+			err = json.Unmarshal(m.value, &s.msg)
+		case CodeKey:
+			err = json.Unmarshal(m.value, &s.code)
+		case inlineKey:
+			err = json.Unmarshal(m.value, &s.inline)
+		case CauseKey:
+			s.err, err = decodeCause(m.value)
+		case CausesKey:
+			var raws []json.RawMessage
+			if err = json.Unmarshal(m.value, &raws); err != nil {
+				break
+			}
+
+			s.errs = make([]error, 0, len(raws))
+			for _, raw := range raws {
+				var cause error
+				if cause, err = decodeCause(raw); err != nil {
+					break
+				}
+
+				s.errs = append(s.errs, cause)
+			}
+		case AttrsKey:
+			var attrs []member
+			if attrs, err = decodeObject(m.value); err != nil {
+				break
+			}
+
+			for _, attr := range attrs {
+				var value slog.Value
+				if value, err = decodeValue(attr.value, attr.key, kinds); err != nil {
+					break
+				}
+
+				s.attrs = append(s.attrs, slog.Attr{Key: attr.key, Value: value})
+			}
+		case slog.SourceKey:
+			s.src = &slog.Source{}
+			err = json.Unmarshal(m.value, s.src)
+		case StackKey:
+			var lines []string
+			if err = json.Unmarshal(m.value, &lines); err == nil {
+				s.stack = decodeStack(lines)
+			}
+		default:
+			var value slog.Value
+			if value, err = decodeValue(m.value, m.key, kinds); err == nil {
+				s.attrs = append(s.attrs, slog.Attr{Key: m.key, Value: value})
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("serrors: decoding %q: %w", m.key, err)
+		}
+	}
+
+	return nil
+}
+
+// FromJSON decodes an error encoded with MarshalJSON.
+func FromJSON(data []byte) (error, error) {
+	var s serror
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return s.asError(), nil
+}
+
+// appendJSONError appends the JSON encoding of err to b. Plain errors are
+// encoded as their message, and standard wrappers holding structured errors
+// as objects, so that their structure is kept. As in LogValue, the causes of
+// errors whose message already renders them are only encoded when they have
+// structure of their own, and such errors are then marked with inlineKey.
+// location reports whether sources and stacks are encoded.
+func appendJSONError(b []byte, err error, location bool) []byte {
+	s, ok := asSerror(err)
+	if !ok {
+		if !hasLogValuer(err) {
+			return appendJSONString(b, err.Error())
+		}
+
+		s = serror{msg: err.Error(), inline: true}
+
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			s.err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			s.errs = e.Unwrap()
+		}
+	}
+
+	b = append(b, '{')
+	b = appendJSONString(b, slog.MessageKey)
+	b = append(b, ':')
+	b = appendJSONString(b, s.Message())
+
+	if s.code != "" {
+		b = append(b, ',')
+		b = appendJSONString(b, CodeKey)
+		b = append(b, ':')
+		b = appendJSONString(b, s.code)
+	}
+
+	cause := s.err != nil && (!s.inline || hasLogValuer(s.err))
+	causes := len(s.errs) > 0 && (!s.inline || slices.ContainsFunc(s.errs, hasLogValuer))
+
+	if s.inline && (cause || causes) {
+		b = append(b, ',')
+		b = appendJSONString(b, inlineKey)
+		b = append(b, ":true"...)
+	}
+
+	if cause {
+		b = append(b, ',')
+		b = appendJSONString(b, CauseKey)
+		b = append(b, ':')
+		b = appendJSONError(b, s.err, location)
+	}
+
+	if causes {
+		b = append(b, ',')
+		b = appendJSONString(b, CausesKey)
+		b = append(b, ":["...)
+
+		for i, err := range s.errs {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			b = appendJSONError(b, err, location)
+		}
+
+		b = append(b, ']')
+	}
+
+	if len(s.attrs) > 0 {
+		b = append(b, ',')
+		b = appendJSONString(b, AttrsKey)
+		b = append(b, ':')
+		b = appendJSONValue(b, slog.GroupValue(s.attrs...))
+	}
+
+	var extra []slog.Attr
+
+	if location && s.hasSource() {
+		extra = append(extra, sourceAttr(s.Source()))
+	}
+
+	if location && s.stack != nil {
+		extra = append(extra, slog.Any(StackKey, s.stack.strings()))
+	}
+
+	if kinds := attrKinds(nil, "", s.attrs); len(kinds) > 0 {
+		extra = append(extra, slog.Attr{Key: AttrKindsKey, Value: slog.GroupValue(kinds...)})
+	}
+
+	b, _ = appendJSONAttrs(b, extra, false)
+
+	return append(b, '}')
+}
+
+// attrKinds appends the kinds of attrs that cannot be told from their JSON
+// encoding alone.
+func attrKinds(kinds []slog.Attr, prefix string, attrs []slog.Attr) []slog.Attr {
+	for _, attr := range attrs {
+		value := attr.Value.Resolve()
+
+		switch value.Kind() {
+		case slog.KindString, slog.KindBool:
+		case slog.KindGroup:
+			groupPrefix := prefix
+			if attr.Key != "" {
+				groupPrefix += attr.Key + "."
+			}
+
+			kinds = attrKinds(kinds, groupPrefix, value.Group())
+		default:
+			kinds = append(kinds, slog.String(prefix+attr.Key, value.Kind().String()))
+		}
+	}
+
+	return kinds
+}
+
+func decodeCause(data []byte) (error, error) {
+	var msg string
+	if err := json.Unmarshal(data, &msg); err == nil {
+		return errors.New(msg), nil
+	}
+
+	var s serror
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return s.asError(), nil
+}
+
+// decodeValue decodes the attribute value at the given dotted path, using the
+// recorded kinds to restore what JSON cannot represent.
+func decodeValue(data []byte, path string, kinds map[string]string) (slog.Value, error) {
+	switch kinds[path] {
+	case slog.KindInt64.String():
+		var v int64
+		err := json.Unmarshal(data, &v)
+		return slog.Int64Value(v), err
+	case slog.KindUint64.String():
+		var v uint64
+		err := json.Unmarshal(data, &v)
+		return slog.Uint64Value(v), err
+	case slog.KindFloat64.String():
+		var v float64
+		err := json.Unmarshal(data, &v)
+		return slog.Float64Value(v), err
+	case slog.KindDuration.String():
+		var v int64
+		err := json.Unmarshal(data, &v)
+		return slog.DurationValue(time.Duration(v)), err
+	case slog.KindTime.String():
+		var v time.Time
+		err := json.Unmarshal(data, &v)
+		return slog.TimeValue(v), err
+	case slog.KindAny.String():
+		var v any
+		err := json.Unmarshal(data, &v)
+		return slog.AnyValue(v), err
+	}
+
+	switch bytes.TrimSpace(data)[0] {
+	case '"':
+		var v string
+		err := json.Unmarshal(data, &v)
+		return slog.StringValue(v), err
+	case 't', 'f':
+		var v bool
+		err := json.Unmarshal(data, &v)
+		return slog.BoolValue(v), err
+	case '{':
+		members, err := decodeObject(data)
+		if err != nil {
+			return slog.Value{}, err
+		}
+
+		attrs := make([]slog.Attr, 0, len(members))
+		for _, m := range members {
+			value, err := decodeValue(m.value, path+"."+m.key, kinds)
+			if err != nil {
+				return slog.Value{}, err
+			}
+
+			attrs = append(attrs, slog.Attr{Key: m.key, Value: value})
+		}
+
+		return slog.GroupValue(attrs...), nil
+	case '[', 'n':
+		var v any
+		err := json.Unmarshal(data, &v)
+		return slog.AnyValue(v), err
+	default:
+		var v json.Number
+		if err := json.Unmarshal(data, &v); err != nil {
+			return slog.Value{}, err
+		}
+
+		if i, err := v.Int64(); err == nil {
+			return slog.Int64Value(i), nil
+		}
+
+		f, err := v.Float64()
+
+		return slog.Float64Value(f), err
+	}
+}
+
+// member is a member of a JSON object.
+type member struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeObject decodes a JSON object, preserving the order of its members.
+func decodeObject(data []byte) ([]member, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, errors.New("serrors: expected a JSON object")
+	}
+
+	var members []member
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var m member
+		m.key, _ = tok.(string)
+
+		if err := dec.Decode(&m.value); err != nil {
+			return nil, err
+		}
+
+		members = append(members, m)
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// decodeStack restores the frames encoded as "function file:line".
+func decodeStack(lines []string) *stack {
+	st := &stack{}
+
+	for _, line := range lines {
+		var frame runtime.Frame
+
+		function, location, _ := strings.Cut(line, " ")
+		file, lineNo, _ := cutLast(location, ":")
+
+		frame.Function = function
+		frame.File = file
+		frame.Line, _ = strconv.Atoi(lineNo)
+
+		st.symbols = append(st.symbols, frame)
+	}
+
+	return st
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+
+	return s[:i], s[i+len(sep):], true
+}
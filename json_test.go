@@ -0,0 +1,280 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSerror_JSON_RoundTrip(t *testing.T) {
+	at := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	tests := []struct {
+		name   string
+		create func() error
+	}{
+		{
+			name: "message only",
+			create: func() error {
+				return NewError("user not found")
+			},
+		},
+		{
+			name: "attribute kinds",
+			create: func() error {
+				return NewError("test error",
+					slog.String("string", "value"),
+					slog.Int64("int64", -42),
+					slog.Uint64("uint64", 1<<63),
+					slog.Float64("float64", 98.0),
+					slog.Bool("bool", true),
+					slog.Duration("duration", 1500*time.Millisecond),
+					slog.Time("time", at),
+					slog.Group("group",
+						slog.Int("status", 503),
+						slog.Group("nested", slog.Duration("elapsed", time.Second))),
+					slog.Any("any", map[string]any{"key": "value"}),
+				)
+			},
+		},
+		{
+			name: "nested causes and code",
+			create: func() error {
+				inner := NewError("validation failed", Code("validation"), slog.String("field", "email"))
+				return WrapError("handler error", WrapError("request failed", inner, slog.Int("retry", 3)))
+			},
+		},
+		{
+			name: "standard cause",
+			create: func() error {
+				return WrapError("fetch failed", errors.New("connection refused"), slog.String("url", "/users"))
+			},
+		},
+		{
+			name: "multiple causes",
+			create: func() error {
+				return WrapErrors("batch failed", []error{
+					errors.New("a"),
+					NewError("b", slog.Int("row", 2)),
+				})
+			},
+		},
+		{
+			name: "Errorf",
+			create: func() error {
+				return Errorf("fetch %s: %w", "users", NewError("timeout", slog.Int("ms", 100)), slog.Int("retry", 1))
+			},
+		},
+		{
+			name: "message containing the cause",
+			create: func() error {
+				return WrapError("read EOF", io.EOF)
+			},
+		},
+		{
+			name: "message equal to the cause",
+			create: func() error {
+				return WrapError("x", errors.New("x"))
+			},
+		},
+		{
+			name: "With on a standard error",
+			create: func() error {
+				return With(errors.New("timeout"), slog.String("user_id", "123"))
+			},
+		},
+		{
+			name: "fmt.Errorf around serror",
+			create: func() error {
+				return WrapError("handler error", fmt.Errorf("decode: %w", NewError("eof", slog.Int("offset", 12))))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.create()
+
+			data, err := json.Marshal(original)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+
+			decoded, err := FromJSON(data)
+			if err != nil {
+				t.Fatalf("FromJSON(%s) error = %v", data, err)
+			}
+
+			if decoded.Error() != original.Error() {
+				t.Errorf("Error() = %q, want %q\nJSON: %s", decoded.Error(), original.Error(), data)
+			}
+
+			expectedAttrs := slices.Collect(AllAttrs(original))
+			actualAttrs := slices.Collect(AllAttrs(decoded))
+			if len(actualAttrs) != len(expectedAttrs) {
+				t.Fatalf("Attrs = %v, want %v", actualAttrs, expectedAttrs)
+			}
+			for i := range expectedAttrs {
+				if !attrsEqual(actualAttrs[i], expectedAttrs[i]) {
+					t.Errorf("Attr %d = %v (%v), want %v (%v)", i,
+						actualAttrs[i], actualAttrs[i].Value.Kind(),
+						expectedAttrs[i], expectedAttrs[i].Value.Kind())
+				}
+			}
+
+			if CodeOf(decoded) != CodeOf(original) {
+				t.Errorf("CodeOf() = %q, want %q", CodeOf(decoded), CodeOf(original))
+			}
+
+			again, err := json.Marshal(decoded)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if !bytes.Equal(again, data) {
+				t.Errorf("Re-encoded JSON differs:\n%s\n%s", again, data)
+			}
+		})
+	}
+}
+
+func TestSerror_JSON_Shape(t *testing.T) {
+	err := WrapError("fetch failed", errors.New("timeout"),
+		Code("fetch"),
+		slog.String("user_id", "123"),
+		slog.Int("retry", 3))
+
+	data, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatalf("json.Marshal() error = %v", marshalErr)
+	}
+
+	expected := `{"msg":"fetch failed","code":"fetch","cause":"timeout","attrs":{"user_id":"123","retry":3},"attr_kinds":{"retry":"Int64"}}`
+	if string(data) != expected {
+		t.Errorf("json.Marshal() = %s, want %s", data, expected)
+	}
+}
+
+func TestSerror_JSON_ReservedKeys(t *testing.T) {
+	for _, attr := range []slog.Attr{
+		slog.String(slog.MessageKey, "overridden"),
+		slog.Int(CodeKey, 500),
+		slog.String(CauseKey, "db"),
+		slog.Int(CausesKey, 2),
+		slog.String(AttrsKey, "x"),
+		slog.String(slog.SourceKey, "db"),
+		slog.String(StackKey, "x"),
+		slog.Bool("inline", true),
+		slog.String(AttrKindsKey, "x"),
+	} {
+		t.Run(attr.Key, func(t *testing.T) {
+			original := WrapError("fetch failed", NewError("timeout"), attr)
+
+			data, err := json.Marshal(original)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+
+			decoded, err := FromJSON(data)
+			if err != nil {
+				t.Fatalf("FromJSON(%s) error = %v", data, err)
+			}
+
+			if decoded.Error() != original.Error() {
+				t.Errorf("Error() = %q, want %q", decoded.Error(), original.Error())
+			}
+
+			if CodeOf(decoded) != "" {
+				t.Errorf("CodeOf() = %q, want none", CodeOf(decoded))
+			}
+
+			attrs := Attrs(decoded)
+			if len(attrs) != 1 || !attrsEqual(attrs[0], attr) {
+				t.Errorf("Attrs() = %v, want [%v]", attrs, attr)
+			}
+		})
+	}
+}
+
+func TestSerror_JSON_StackAndSource(t *testing.T) {
+	setStackCapture(t, StackAll)
+	setCaptureSource(t, true, false)
+
+	original := NewError("test error")
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	decoded, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(Source(decoded), Source(original)) {
+		t.Errorf("Source() = %v, want %v", Source(decoded), Source(original))
+	}
+
+	expected := StackTrace(original)
+	actual := StackTrace(decoded)
+	if len(actual) != len(expected) {
+		t.Fatalf("StackTrace() has %d frames, want %d", len(actual), len(expected))
+	}
+	for i := range expected {
+		if actual[i].Function != expected[i].Function || actual[i].File != expected[i].File || actual[i].Line != expected[i].Line {
+			t.Errorf("Frame %d = %v, want %v", i, actual[i], expected[i])
+		}
+	}
+}
+
+func TestFromJSON_LogOutput(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "error",
+		WrapError("fetch failed", NewError("timeout", slog.Float64("after", 1.5)), slog.Int("retry", 3)))
+
+	var record struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	decoded, err := FromJSON(record.Error)
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+
+	if decoded.Error() != "fetch failed cause=[timeout after=1.5] retry=3" {
+		t.Errorf("Error() = %q", decoded.Error())
+	}
+	if v, ok := LookupInt64(decoded, "retry"); !ok || v != 3 {
+		t.Errorf("LookupInt64(retry) = %d, %v", v, ok)
+	}
+}
+
+func TestFromJSON_Errors(t *testing.T) {
+	for _, data := range []string{`"plain"`, `{"msg":1}`, `{"msg":"x","cause":[1]}`, `{`} {
+		if _, err := FromJSON([]byte(data)); err == nil {
+			t.Errorf("FromJSON(%s) should fail", data)
+		}
+	}
+}
+
+func attrsEqual(a, b slog.Attr) bool {
+	if a.Key != b.Key || a.Value.Kind() != b.Value.Kind() {
+		return false
+	}
+
+	if a.Value.Kind() == slog.KindAny {
+		return reflect.DeepEqual(a.Value.Any(), b.Value.Any())
+	}
+
+	return a.Equal(b)
+}
@@ -0,0 +1,153 @@
+package serrors
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// LogMode selects how the chain of an error is logged.
+type LogMode int
+
+const (
+	// LogModeDefault uses DefaultLogMode.
+	LogModeDefault LogMode = iota
+	// LogNested nests every cause in the group of the error that wraps it,
+	// under CauseKey or CausesKey.
+	LogNested
+	// LogFlat logs every level below the outermost error as an element of a
+	// flat array under CausesKey, followed by the message of the root cause
+	// under RootCauseKey.
+	LogFlat
+)
+
+var (
+	// DefaultLogMode is the mode used by LogValue, and by handlers without a
+	// mode of their own.
+	DefaultLogMode = LogNested
+	// RootCauseKey is the key under which the message of the root cause is
+	// logged in LogFlat mode.
+	RootCauseKey = "root_cause"
+)
+
+func (m LogMode) resolve() LogMode {
+	if m == LogModeDefault {
+		return DefaultLogMode
+	}
+
+	return m
+}
+
+// flatValue returns the value of err in LogFlat mode. Every error below err
+// becomes one element of the causes array, outermost first. The root cause
+// is found by following the first branch of multi-cause errors.
+func flatValue(err error) slog.Value {
+	var levels valueList
+	for _, cause := range unwrapAll(err) {
+		levels = appendLevels(levels, cause)
+	}
+
+	if len(levels) == 0 {
+		return slog.GroupValue(levelAttrs(err)...)
+	}
+
+	return slog.GroupValue(levelAttrs(err,
+		slog.Any(CausesKey, levels),
+		slog.String(RootCauseKey, rootMessage(err)),
+	)...)
+}
+
+func appendLevels(levels valueList, err error) valueList {
+	levels = append(levels, slog.GroupValue(levelAttrs(err)...))
+
+	for _, cause := range unwrapAll(err) {
+		levels = appendLevels(levels, cause)
+	}
+
+	return levels
+}
+
+// levelAttrs returns the logged attributes of err alone, without its causes.
+func levelAttrs(err error, extra ...slog.Attr) []slog.Attr {
+	if s, ok := asSerror(err); ok {
+		return s.levelAttrs(extra...)
+	}
+
+	if v, ok := err.(slog.LogValuer); ok {
+		if value := v.LogValue().Resolve(); value.Kind() == slog.KindGroup {
+			return slices.Concat(value.Group(), extra)
+		}
+	}
+
+	return append([]slog.Attr{slog.String(slog.MessageKey, err.Error())}, extra...)
+}
+
+func rootMessage(err error) string {
+	for {
+		causes := unwrapAll(err)
+		if len(causes) == 0 {
+			break
+		}
+
+		err = causes[0]
+	}
+
+	if s, ok := asSerror(err); ok {
+		return s.Message()
+	}
+
+	return err.Error()
+}
+
+// unwrapAll returns the errors directly wrapped by err.
+func unwrapAll(err error) []error {
+	if s, ok := asSerror(err); ok {
+		return s.Causes()
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := e.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	}
+
+	return nil
+}
+
+// valueList is a list of logged values, rendered as a JSON array.
+type valueList []slog.Value
+
+func (l valueList) String() string {
+	var b strings.Builder
+
+	_ = b.WriteByte('[')
+
+	for i, v := range l {
+		if i > 0 {
+			_ = b.WriteByte(' ')
+		}
+
+		_, _ = b.WriteString(v.String())
+	}
+
+	_ = b.WriteByte(']')
+
+	return b.String()
+}
+
+func (l valueList) MarshalJSON() ([]byte, error) {
+	b := []byte{'['}
+
+	for i, v := range l {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		b = appendJSONValue(b, v)
+	}
+
+	return append(b, ']'), nil
+}
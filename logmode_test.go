@@ -0,0 +1,143 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestLogMode_Flat(t *testing.T) {
+	chain := WrapError("handler error",
+		fmt.Errorf("fetch: %w",
+			WrapError("request failed", errors.New("connection refused"),
+				slog.String("request_id", "req-123"))),
+		Code("handler"),
+		slog.String("handler", "UserHandler"))
+
+	expected := map[string]any{
+		"msg":  "handler error",
+		"code": "handler",
+		"causes": []any{
+			map[string]any{"msg": "fetch: request failed cause=[connection refused] request_id=req-123"},
+			map[string]any{"msg": "request failed", "request_id": "req-123"},
+			map[string]any{"msg": "connection refused"},
+		},
+		"root_cause": "connection refused",
+		"handler":    "UserHandler",
+	}
+
+	t.Run("global", func(t *testing.T) {
+		previous := DefaultLogMode
+		DefaultLogMode = LogFlat
+		t.Cleanup(func() { DefaultLogMode = previous })
+
+		var buf bytes.Buffer
+		slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "error", chain)
+
+		if actual := decodeErrorGroup(t, buf.Bytes()); !reflect.DeepEqual(actual, any(expected)) {
+			t.Errorf("error = %#v, want %#v", actual, expected)
+		}
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler := NewHandler(slog.NewJSONHandler(&buf, nil), &HandlerOptions{Mode: LogFlat})
+		slog.New(handler).Error("failed", "error", chain)
+
+		if actual := decodeErrorGroup(t, buf.Bytes()); !reflect.DeepEqual(actual, any(expected)) {
+			t.Errorf("error = %#v, want %#v", actual, expected)
+		}
+	})
+
+	t.Run("handler overrides global", func(t *testing.T) {
+		previous := DefaultLogMode
+		DefaultLogMode = LogFlat
+		t.Cleanup(func() { DefaultLogMode = previous })
+
+		var buf bytes.Buffer
+		handler := NewHandler(slog.NewJSONHandler(&buf, nil), &HandlerOptions{Mode: LogNested})
+		slog.New(handler).Error("failed", "error", chain)
+
+		group, _ := decodeErrorGroup(t, buf.Bytes()).(map[string]any)
+		if _, ok := group["cause"].(map[string]any); !ok {
+			t.Errorf("Expected a nested cause, got %#v", group)
+		}
+		if _, exists := group["causes"]; exists {
+			t.Errorf("Unexpected flat causes in nested mode")
+		}
+	})
+}
+
+func TestLogMode_Flat_Shapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected map[string]any
+	}{
+		{
+			name: "no causes",
+			err:  NewError("user not found", slog.String("user_id", "123")),
+			expected: map[string]any{
+				"msg":     "user not found",
+				"user_id": "123",
+			},
+		},
+		{
+			name: "multiple causes",
+			err: WrapErrors("batch failed", []error{
+				NewError("a failed", slog.Int("row", 1)),
+				errors.Join(errors.New("b failed"), errors.New("c failed")),
+			}),
+			expected: map[string]any{
+				"msg": "batch failed",
+				"causes": []any{
+					map[string]any{"msg": "a failed", "row": float64(1)},
+					map[string]any{"msg": "b failed\nc failed"},
+					map[string]any{"msg": "b failed"},
+					map[string]any{"msg": "c failed"},
+				},
+				"root_cause": "a failed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/handler", func(t *testing.T) {
+			var buf bytes.Buffer
+			handler := NewHandler(slog.NewJSONHandler(&buf, nil), &HandlerOptions{Mode: LogFlat})
+			slog.New(handler).Error("failed", "error", tt.err)
+
+			if actual := decodeErrorGroup(t, buf.Bytes()); !reflect.DeepEqual(actual, any(tt.expected)) {
+				t.Errorf("error = %#v, want %#v", actual, tt.expected)
+			}
+		})
+
+		t.Run(tt.name+"/global", func(t *testing.T) {
+			previous := DefaultLogMode
+			DefaultLogMode = LogFlat
+			t.Cleanup(func() { DefaultLogMode = previous })
+
+			var buf bytes.Buffer
+			slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "error", tt.err)
+
+			if actual := decodeErrorGroup(t, buf.Bytes()); !reflect.DeepEqual(actual, any(tt.expected)) {
+				t.Errorf("error = %#v, want %#v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func decodeErrorGroup(t *testing.T, data []byte) any {
+	t.Helper()
+
+	var logOutput map[string]any
+	if err := json.Unmarshal(data, &logOutput); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	return logOutput["error"]
+}
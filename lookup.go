@@ -0,0 +1,149 @@
+package serrors
+
+import (
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+)
+
+// Lookup returns the value of the attribute with the given key, searching the
+// whole chain of err, outermost first. Values implementing [slog.LogValuer]
+// are resolved. A dotted key such as "http.status" descends into
+// [slog.Group] attributes.
+func Lookup(err error, key string) (slog.Value, bool) {
+	var (
+		value slog.Value
+		found bool
+	)
+
+	walk(err, func(s serror, _ int) bool {
+		value, found = lookupAttrs(s.attrs, key)
+		return !found
+	})
+
+	return value, found
+}
+
+// LookupString returns the string value of the attribute with the given key.
+func LookupString(err error, key string) (string, bool) {
+	v, ok := Lookup(err, key)
+	if !ok || v.Kind() != slog.KindString {
+		return "", false
+	}
+
+	return v.String(), true
+}
+
+// LookupInt64 returns the integer value of the attribute with the given key.
+// Unsigned values are accepted when they fit in an int64.
+func LookupInt64(err error, key string) (int64, bool) {
+	v, ok := Lookup(err, key)
+	if !ok {
+		return 0, false
+	}
+
+	switch v.Kind() {
+	case slog.KindInt64:
+		return v.Int64(), true
+	case slog.KindUint64:
+		if v.Uint64() <= math.MaxInt64 {
+			return int64(v.Uint64()), true
+		}
+	}
+
+	return 0, false
+}
+
+// LookupUint64 returns the unsigned integer value of the attribute with the
+// given key. Signed values are accepted when they are not negative.
+func LookupUint64(err error, key string) (uint64, bool) {
+	v, ok := Lookup(err, key)
+	if !ok {
+		return 0, false
+	}
+
+	switch v.Kind() {
+	case slog.KindUint64:
+		return v.Uint64(), true
+	case slog.KindInt64:
+		if v.Int64() >= 0 {
+			return uint64(v.Int64()), true
+		}
+	}
+
+	return 0, false
+}
+
+// LookupFloat64 returns the floating-point value of the attribute with the
+// given key.
+func LookupFloat64(err error, key string) (float64, bool) {
+	v, ok := Lookup(err, key)
+	if !ok || v.Kind() != slog.KindFloat64 {
+		return 0, false
+	}
+
+	return v.Float64(), true
+}
+
+// LookupBool returns the boolean value of the attribute with the given key.
+func LookupBool(err error, key string) (bool, bool) {
+	v, ok := Lookup(err, key)
+	if !ok || v.Kind() != slog.KindBool {
+		return false, false
+	}
+
+	return v.Bool(), true
+}
+
+// LookupDuration returns the duration value of the attribute with the given
+// key.
+func LookupDuration(err error, key string) (time.Duration, bool) {
+	v, ok := Lookup(err, key)
+	if !ok || v.Kind() != slog.KindDuration {
+		return 0, false
+	}
+
+	return v.Duration(), true
+}
+
+// LookupTime returns the time value of the attribute with the given key.
+func LookupTime(err error, key string) (time.Time, bool) {
+	v, ok := Lookup(err, key)
+	if !ok || v.Kind() != slog.KindTime {
+		return time.Time{}, false
+	}
+
+	return v.Time(), true
+}
+
+func lookupAttrs(attrs []slog.Attr, key string) (slog.Value, bool) {
+	for _, attr := range attrs {
+		value := attr.Value.Resolve()
+
+		if attr.Key == key {
+			return value, true
+		}
+
+		if value.Kind() != slog.KindGroup {
+			continue
+		}
+
+		// Groups with an empty key are inlined, as slog handlers do.
+		if attr.Key == "" {
+			if v, ok := lookupAttrs(value.Group(), key); ok {
+				return v, true
+			}
+
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(key, attr.Key+"."); ok {
+			if v, ok := lookupAttrs(value.Group(), rest); ok {
+				return v, true
+			}
+		}
+	}
+
+	return slog.Value{}, false
+}
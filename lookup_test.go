@@ -0,0 +1,168 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type userValuer struct {
+	id string
+}
+
+func (u userValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id", u.id), slog.String("role", "admin"))
+}
+
+type retryValuer int
+
+func (r retryValuer) LogValue() slog.Value {
+	return slog.Int64Value(int64(r))
+}
+
+func TestLookup(t *testing.T) {
+	err := WrapError("handler error",
+		fmt.Errorf("fetch: %w", WrapError("request failed",
+			errors.New("timeout"),
+			slog.String("request_id", "req-123"),
+			slog.Int("retry_count", 3),
+			slog.Group("http",
+				slog.Int("status", 503),
+				slog.Group("request", slog.String("method", "GET")),
+			),
+			slog.Any("user", userValuer{id: "u-1"}),
+		)),
+		slog.String("request_id", "req-outer"),
+		slog.String("dotted.key", "literal"),
+		slog.Group("", slog.String("inlined", "yes")),
+	)
+
+	tests := []struct {
+		name     string
+		key      string
+		expected slog.Value
+		found    bool
+	}{
+		{
+			name:     "outermost attribute",
+			key:      "request_id",
+			expected: slog.StringValue("req-outer"),
+			found:    true,
+		},
+		{
+			name:     "attribute below fmt.Errorf",
+			key:      "retry_count",
+			expected: slog.Int64Value(3),
+			found:    true,
+		},
+		{
+			name:     "dotted path into group",
+			key:      "http.status",
+			expected: slog.Int64Value(503),
+			found:    true,
+		},
+		{
+			name:     "dotted path into nested group",
+			key:      "http.request.method",
+			expected: slog.StringValue("GET"),
+			found:    true,
+		},
+		{
+			name:     "dotted path into resolved LogValuer",
+			key:      "user.role",
+			expected: slog.StringValue("admin"),
+			found:    true,
+		},
+		{
+			name:     "literal key containing a dot",
+			key:      "dotted.key",
+			expected: slog.StringValue("literal"),
+			found:    true,
+		},
+		{
+			name:     "attribute in inlined group",
+			key:      "inlined",
+			expected: slog.StringValue("yes"),
+			found:    true,
+		},
+		{
+			name:  "missing key",
+			key:   "missing",
+			found: false,
+		},
+		{
+			name:  "missing key in group",
+			key:   "http.missing",
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, found := Lookup(err, tt.key)
+			if found != tt.found {
+				t.Fatalf("Lookup() found = %v, want %v", found, tt.found)
+			}
+			if found && !actual.Equal(tt.expected) {
+				t.Errorf("Lookup() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLookup_Typed(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := NewError("test error",
+		slog.String("name", "john"),
+		slog.Int("count", 42),
+		slog.Int("negative", -1),
+		slog.Uint64("big", 1<<63),
+		slog.Float64("score", 98.5),
+		slog.Bool("valid", true),
+		slog.Duration("elapsed", 2*time.Second),
+		slog.Time("at", now),
+		slog.Any("retries", retryValuer(5)),
+	)
+
+	if v, ok := LookupString(err, "name"); !ok || v != "john" {
+		t.Errorf("LookupString() = %q, %v", v, ok)
+	}
+	if _, ok := LookupString(err, "count"); ok {
+		t.Errorf("LookupString() should not match an int attribute")
+	}
+	if v, ok := LookupInt64(err, "count"); !ok || v != 42 {
+		t.Errorf("LookupInt64() = %d, %v", v, ok)
+	}
+	if v, ok := LookupInt64(err, "retries"); !ok || v != 5 {
+		t.Errorf("LookupInt64() on LogValuer = %d, %v", v, ok)
+	}
+	if _, ok := LookupInt64(err, "big"); ok {
+		t.Errorf("LookupInt64() should not match an overflowing uint64")
+	}
+	if v, ok := LookupUint64(err, "big"); !ok || v != 1<<63 {
+		t.Errorf("LookupUint64() = %d, %v", v, ok)
+	}
+	if _, ok := LookupUint64(err, "negative"); ok {
+		t.Errorf("LookupUint64() should not match a negative int64")
+	}
+	if v, ok := LookupFloat64(err, "score"); !ok || v != 98.5 {
+		t.Errorf("LookupFloat64() = %v, %v", v, ok)
+	}
+	if v, ok := LookupBool(err, "valid"); !ok || !v {
+		t.Errorf("LookupBool() = %v, %v", v, ok)
+	}
+	if v, ok := LookupDuration(err, "elapsed"); !ok || v != 2*time.Second {
+		t.Errorf("LookupDuration() = %v, %v", v, ok)
+	}
+	if _, ok := LookupDuration(err, "count"); ok {
+		t.Errorf("LookupDuration() should not match an int attribute")
+	}
+	if v, ok := LookupTime(err, "at"); !ok || !v.Equal(now) {
+		t.Errorf("LookupTime() = %v, %v", v, ok)
+	}
+	if _, ok := LookupTime(nil, "at"); ok {
+		t.Errorf("LookupTime() on nil error should not match")
+	}
+}
@@ -0,0 +1,42 @@
+package serrors
+
+// NilCausePolicy controls what happens when an error is wrapped around a nil
+// cause, which usually means a success is being turned into a failure.
+type NilCausePolicy int
+
+const (
+	// NilCauseAllow creates the error as if no cause was given.
+	NilCauseAllow NilCausePolicy = iota
+	// NilCausePanic panics. It is meant to be enabled in tests.
+	NilCausePanic
+	// NilCauseTag creates the error with the code CodeNilCause, replacing
+	// any other code.
+	NilCauseTag
+)
+
+// NilCause is the policy applied by [WrapError], [Wrap], [WrapErrorContext]
+// and [Definition.Wrap] when the cause is nil. Use [WrapIf] to wrap errors
+// that may be nil.
+var NilCause = NilCauseAllow
+
+// CodeNilCause is the code of errors created around a nil cause under
+// NilCauseTag.
+var CodeNilCause = Register(CodeInfo{
+	Code:        "serrors.nil_cause",
+	Description: "An error was wrapped around a nil cause",
+})
+
+func nilCause(s serror) serror {
+	if s.err != nil {
+		return s
+	}
+
+	switch NilCause {
+	case NilCausePanic:
+		panic("serrors: wrapping a nil cause: " + s.msg)
+	case NilCauseTag:
+		s.attrs = append(s.attrs[:len(s.attrs):len(s.attrs)], Code(CodeNilCause))
+	}
+
+	return s
+}
@@ -0,0 +1,112 @@
+package serrors
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func setNilCause(t *testing.T, policy NilCausePolicy) {
+	t.Helper()
+
+	previous := NilCause
+	NilCause = policy
+	t.Cleanup(func() { NilCause = previous })
+}
+
+func TestWrapIf(t *testing.T) {
+	if err := WrapIf("fetch failed", nil, slog.String("key", "value")); err != nil {
+		t.Errorf("WrapIf(nil) = %v, want nil", err)
+	}
+
+	cause := errors.New("timeout")
+	err := WrapIf("fetch failed", cause, slog.String("key", "value"))
+	if err == nil {
+		t.Fatalf("WrapIf() lost the error")
+	}
+	if err.Error() != "fetch failed cause=[timeout] key=value" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("Expected errors.Is to find the cause")
+	}
+}
+
+func TestNilCause(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       NilCausePolicy
+		wrap         func() error
+		expectedCode string
+	}{
+		{
+			name:   "allow",
+			policy: NilCauseAllow,
+			wrap: func() error {
+				return WrapError("fetch failed", nil, Code("fetch"))
+			},
+			expectedCode: "fetch",
+		},
+		{
+			name:   "tag WrapError",
+			policy: NilCauseTag,
+			wrap: func() error {
+				return WrapError("fetch failed", nil, Code("fetch"))
+			},
+			expectedCode: CodeNilCause,
+		},
+		{
+			name:   "tag Definition.Wrap",
+			policy: NilCauseTag,
+			wrap: func() error {
+				return errTestForbidden.Wrap(nil)
+			},
+			expectedCode: CodeNilCause,
+		},
+		{
+			name:   "tag ignores non-nil causes",
+			policy: NilCauseTag,
+			wrap: func() error {
+				return WrapError("fetch failed", errors.New("timeout"))
+			},
+			expectedCode: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setNilCause(t, tt.policy)
+
+			err := tt.wrap()
+			if err == nil {
+				t.Fatalf("Expected an error")
+			}
+			if CodeOf(err) != tt.expectedCode {
+				t.Errorf("CodeOf() = %q, want %q", CodeOf(err), tt.expectedCode)
+			}
+		})
+	}
+}
+
+func TestNilCause_Panic(t *testing.T) {
+	setNilCause(t, NilCausePanic)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected WrapError to panic on a nil cause")
+		}
+	}()
+
+	_ = WrapError("fetch failed", nil)
+}
+
+func TestNilCause_PanicWrapIf(t *testing.T) {
+	setNilCause(t, NilCausePanic)
+
+	if err := WrapIf("fetch failed", nil); err != nil {
+		t.Errorf("WrapIf(nil) = %v, want nil", err)
+	}
+	if _, ok := LookupCode(CodeNilCause); !ok {
+		t.Errorf("Expected %q to be registered", CodeNilCause)
+	}
+}
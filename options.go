@@ -0,0 +1,63 @@
+package serrors
+
+import "log/slog"
+
+// option configures the construction of an error. Options travel through the
+// attribute list of the constructors as attributes with an empty key, so
+// they can be mixed freely with regular attributes.
+type option func(*options)
+
+type options struct {
+	skip int
+	code string
+}
+
+func optionAttr(o option) slog.Attr {
+	return slog.Any("", o)
+}
+
+// CallerSkip returns an option that skips n additional frames when capturing
+// the caller and the stack. Helpers that wrap the constructors use it to
+// attribute errors to their own callers.
+func CallerSkip(n int) slog.Attr {
+	return optionAttr(func(o *options) {
+		o.skip += n
+	})
+}
+
+// extractOptions separates the options from the regular attributes. The
+// input slice is not modified.
+func extractOptions(attrs []slog.Attr) ([]slog.Attr, options) {
+	var opts options
+
+	for i, attr := range attrs {
+		if _, ok := asOption(attr); !ok {
+			continue
+		}
+
+		filtered := make([]slog.Attr, i, len(attrs)-1)
+		copy(filtered, attrs[:i])
+
+		for _, attr := range attrs[i:] {
+			if o, ok := asOption(attr); ok {
+				o(&opts)
+			} else {
+				filtered = append(filtered, attr)
+			}
+		}
+
+		return filtered, opts
+	}
+
+	return attrs, opts
+}
+
+func asOption(attr slog.Attr) (option, bool) {
+	if attr.Key != "" || attr.Value.Kind() != slog.KindAny {
+		return nil, false
+	}
+
+	o, ok := attr.Value.Any().(option)
+
+	return o, ok
+}
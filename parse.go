@@ -0,0 +1,287 @@
+package serrors
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parse reconstructs a structured error from the text produced by Error, in
+// either the RenderV1 or the RenderV2 format. Causes are parsed recursively,
+// including the plain errors at the root of the chain. Attribute values are
+// restored as strings; groups rendered by RenderV1 are restored as groups,
+// while the dotted keys of RenderV2 are kept as they are.
+//
+// RenderV1 is ambiguous when values contain spaces followed by '=', or
+// unbalanced brackets, in which case the result is a best effort.
+func Parse(s string) (error, error) {
+	parsed, err := parseError(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.asError(), nil
+}
+
+func parseError(s string) (serror, error) {
+	var parsed serror
+
+	// A message is only taken as quoted by RenderV2 when the quotes span it
+	// entirely; RenderV1 messages may start with a quote too.
+	rest := s
+	if msg, n, err := parseQuoted(rest); err == nil && (n == len(rest) || nextKey(rest, n) == n) {
+		parsed.msg, rest = msg, rest[n:]
+	} else {
+		end := nextKey(rest, 0)
+		parsed.msg, rest = rest[:end], rest[end:]
+	}
+
+	for rest != "" {
+		if rest[0] != ' ' {
+			return serror{}, parseErr("unexpected text", textAttr(rest))
+		}
+
+		key, n, err := parseKey(rest[1:])
+		if err != nil {
+			return serror{}, err
+		}
+
+		rest = rest[1+n+1:]
+
+		var attr slog.Attr
+		if n, err = parseField(&parsed, key, rest, &attr); err != nil {
+			return serror{}, err
+		}
+
+		if attr.Key != "" {
+			parsed.attrs = append(parsed.attrs, attr)
+		}
+
+		rest = rest[n:]
+	}
+
+	return parsed, nil
+}
+
+// parseField parses the value of the field with the given key at the start
+// of rest, storing it in s or attr. It returns the length of the value.
+func parseField(s *serror, key, rest string, attr *slog.Attr) (int, error) {
+	// RenderV1 frames causes as cause=[...] and causes=[[...] [...]]. Text
+	// that merely starts with a bracket is parsed as a value instead.
+	if key == CauseKey || key == CausesKey {
+		if end, ok := framed(rest); ok {
+			if key == CauseKey {
+				if cause, err := parseError(rest[1:end]); err == nil {
+					s.err = cause.asError()
+					return end + 1, nil
+				}
+			} else if causes, err := parseCauseList(rest[1:end]); err == nil {
+				s.errs = append(s.errs, causes...)
+				return end + 1, nil
+			}
+		}
+	}
+
+	value, n, err := parseValue(rest)
+	if err != nil {
+		return 0, err
+	}
+
+	if key == CauseKey || isCausesIndex(key) {
+		cause, err := parseError(value)
+		if err != nil {
+			return 0, err
+		}
+
+		if key == CauseKey {
+			s.err = cause.asError()
+		} else {
+			s.errs = append(s.errs, cause.asError())
+		}
+
+		return n, nil
+	}
+
+	*attr = slog.String(key, value)
+
+	// RenderV1 renders groups as key=[k=v ...].
+	if end, ok := framed(rest); ok {
+		group, err := parseError(" " + rest[1:end])
+		if err == nil && group.msg == "" && group.err == nil && group.errs == nil {
+			*attr = slog.Attr{Key: key, Value: slog.GroupValue(group.attrs...)}
+			return end + 1, nil
+		}
+	}
+
+	return n, nil
+}
+
+// parseValue parses a quoted value, or a bare one extending to the next key.
+func parseValue(rest string) (string, int, error) {
+	if strings.HasPrefix(rest, `"`) {
+		if value, n, err := parseQuoted(rest); err == nil && (n == len(rest) || rest[n] == ' ') {
+			return value, n, nil
+		}
+	}
+
+	end := nextKey(rest, 0)
+
+	return rest[:end], end, nil
+}
+
+// parseCauseList parses the content of causes=[[a] [b]].
+func parseCauseList(list string) ([]error, error) {
+	var causes []error
+
+	for list != "" {
+		if !strings.HasPrefix(list, "[") {
+			return nil, parseErr("malformed causes", textAttr(list))
+		}
+
+		end, ok := matchBracket(list)
+		if !ok {
+			return nil, parseErr("unterminated cause", textAttr(list))
+		}
+
+		cause, err := parseError(list[1:end])
+		if err != nil {
+			return nil, err
+		}
+
+		causes = append(causes, cause.asError())
+		list = strings.TrimPrefix(list[end+1:], " ")
+	}
+
+	return causes, nil
+}
+
+func parseKey(rest string) (string, int, error) {
+	if strings.HasPrefix(rest, `"`) {
+		key, n, err := parseQuoted(rest)
+		if err != nil {
+			return "", 0, err
+		}
+
+		if !strings.HasPrefix(rest[n:], "=") {
+			return "", 0, parseErr("missing key", textAttr(rest))
+		}
+
+		return key, n, nil
+	}
+
+	n := strings.IndexByte(rest, '=')
+	if n <= 0 {
+		return "", 0, parseErr("missing key", textAttr(rest))
+	}
+
+	return rest[:n], n, nil
+}
+
+func parseQuoted(rest string) (string, int, error) {
+	quoted, err := strconv.QuotedPrefix(rest)
+	if err != nil {
+		return "", 0, parseErr("malformed quoted string", textAttr(rest))
+	}
+
+	value, err := strconv.Unquote(quoted)
+	if err != nil {
+		return "", 0, parseErr("malformed quoted string", textAttr(rest))
+	}
+
+	return value, len(quoted), nil
+}
+
+// nextKey returns the index of the space preceding the next key=value field
+// in s, starting at from, or len(s).
+func nextKey(s string, from int) int {
+	for i := from; i < len(s); i++ {
+		if s[i] == ' ' && isKeyAt(s[i+1:]) {
+			return i
+		}
+	}
+
+	return len(s)
+}
+
+func isKeyAt(s string) bool {
+	if strings.HasPrefix(s, `"`) {
+		quoted, err := strconv.QuotedPrefix(s)
+		return err == nil && strings.HasPrefix(s[len(quoted):], "=")
+	}
+
+	n := strings.IndexAny(s, ` ="`)
+
+	return n > 0 && s[n] == '='
+}
+
+// framed returns the index of the bracket closing the one at the start of s,
+// if it is followed by the end of s or a space, as in the RenderV1 framing.
+func framed(s string) (int, bool) {
+	if !strings.HasPrefix(s, "[") {
+		return 0, false
+	}
+
+	end, ok := matchBracket(s)
+
+	return end, ok && (end+1 == len(s) || s[end+1] == ' ')
+}
+
+// matchBracket returns the index of the bracket closing the one at the start
+// of s.
+func matchBracket(s string) (int, bool) {
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func isCausesIndex(key string) bool {
+	index, ok := strings.CutPrefix(key, CausesKey+".")
+	if !ok {
+		return false
+	}
+
+	_, err := strconv.Atoi(index)
+
+	return err == nil
+}
+
+// asError returns s as an error, choosing the multi-cause type when needed.
+func (s serror) asError() error {
+	if s.errs != nil {
+		return s.multi()
+	}
+
+	return s
+}
+
+func parseErr(msg string, attrs ...slog.Attr) error {
+	return serror{msg: "serrors: " + msg, attrs: attrs}
+}
+
+// textAttr returns an attribute with the beginning of the unparsed text.
+func textAttr(s string) slog.Attr {
+	const maxText = 32
+	if len(s) > maxText {
+		cut := maxText
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+
+		s = s[:cut] + "..."
+	}
+
+	return slog.String("text", s)
+}
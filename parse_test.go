@@ -0,0 +1,226 @@
+package serrors
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		create    func() error
+		rendering Rendering
+		expected  string
+	}{
+		{
+			name: "message only",
+			create: func() error {
+				return NewError("user not found")
+			},
+			expected: "user not found",
+		},
+		{
+			name: "message with attributes",
+			create: func() error {
+				return NewError("user not found", slog.String("user_id", "123"), slog.Int("retry", 3))
+			},
+			expected: "user not found\n  user_id=123\n  retry=3",
+		},
+		{
+			name: "nested causes",
+			create: func() error {
+				inner := NewError("validation failed", slog.String("field", "email"))
+				middle := WrapError("request processing failed", inner, slog.String("request_id", "req-123"))
+				return WrapError("handler error", middle, slog.String("handler", "UserHandler"))
+			},
+			expected: "handler error\n  handler=UserHandler\n  cause: request processing failed\n    request_id=req-123\n    cause: validation failed\n      field=email",
+		},
+		{
+			name: "legacy values with spaces and brackets",
+			create: func() error {
+				return WrapError("parse error", errors.New("bad input"),
+					slog.String("input", "hello world"),
+					slog.String("chars", "[]{}="),
+					slog.Any("data", map[string]string{"key": "value"}))
+			},
+			expected: "parse error\n  input=hello world\n  chars=[]{}=\n  data=map[key:value]\n  cause: bad input",
+		},
+		{
+			name: "legacy groups",
+			create: func() error {
+				return NewError("request failed", slog.Group("http",
+					slog.Int("status", 503),
+					slog.Group("request", slog.String("method", "GET"))),
+					slog.String("after", "x"))
+			},
+			expected: "request failed\n  http=[status=503 request=[method=GET]]\n  after=x",
+		},
+		{
+			name: "legacy multiple causes",
+			create: func() error {
+				return WrapErrors("batch failed", []error{
+					errors.New("a"),
+					NewError("b", slog.Int("row", 2)),
+				}, slog.Int("size", 2))
+			},
+			expected: "batch failed\n  size=2\n  cause[0]: a\n  cause[1]: b\n    row=2",
+		},
+		{
+			name: "legacy empty message",
+			create: func() error {
+				return NewError("", slog.String("key", "value"))
+			},
+			expected: "\n  key=value",
+		},
+		{
+			name: "quoted values",
+			create: func() error {
+				return WrapError("parse error", errors.New("unexpected ] in input"),
+					slog.String("input", "hello world"),
+					slog.String("quoted", `"x" y=z`),
+					slog.String("my key", "v"))
+			},
+			rendering: RenderV2,
+			expected:  "parse error\n  input=hello world\n  quoted=\"x\" y=z\n  my key=v\n  cause: unexpected ] in input",
+		},
+		{
+			name: "quoted nested causes",
+			create: func() error {
+				return WrapError("level 1",
+					WrapError("level 2", errors.New("level 3"), slog.String("input", "a b")),
+					slog.String("level", "1"))
+			},
+			rendering: RenderV2,
+			expected:  "level 1\n  level=1\n  cause: level 2\n    input=a b\n    cause: level 3",
+		},
+		{
+			name: "quoted message",
+			create: func() error {
+				return WrapError("bad a=b", errors.New("EOF"))
+			},
+			rendering: RenderV2,
+			expected:  "bad a=b\n  cause: EOF",
+		},
+		{
+			name: "quoted causes with brackets",
+			create: func() error {
+				return WrapError("fetch", errors.New("[eof]"), slog.String("tag", "[x"))
+			},
+			rendering: RenderV2,
+			expected:  "fetch\n  tag=[x\n  cause: [eof]",
+		},
+		{
+			name: "quoted unbalanced brackets",
+			create: func() error {
+				return WrapErrors("batch", []error{errors.New("[x"), errors.New("y]")})
+			},
+			rendering: RenderV2,
+			expected:  "batch\n  cause[0]: [x\n  cause[1]: y]",
+		},
+		{
+			name: "quoted multiple causes",
+			create: func() error {
+				return WrapErrors("batch failed", []error{errors.New("a"), errors.New("b failed")})
+			},
+			rendering: RenderV2,
+			expected:  "batch failed\n  cause[0]: a\n  cause[1]: b failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setErrorRendering(t, tt.rendering)
+
+			text := tt.create().Error()
+
+			parsed, err := Parse(text)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", text, err)
+			}
+
+			if actual := fmt.Sprintf("%+v", parsed); actual != tt.expected {
+				t.Errorf("Parse(%q) =\n%s\nwant\n%s", text, actual, tt.expected)
+			}
+
+			if parsed.Error() != text {
+				t.Errorf("Parsed Error() = %q, want %q", parsed.Error(), text)
+			}
+		})
+	}
+}
+
+func TestParse_Lookup(t *testing.T) {
+	parsed, err := Parse("handler error cause=[fetch failed cause=[timeout] request_id=req-123] http=[status=503]")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if v, ok := LookupString(parsed, "request_id"); !ok || v != "req-123" {
+		t.Errorf("LookupString(request_id) = %q, %v", v, ok)
+	}
+	if v, ok := LookupString(parsed, "http.status"); !ok || v != "503" {
+		t.Errorf("LookupString(http.status) = %q, %v", v, ok)
+	}
+}
+
+func TestParse_Lenient(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "unterminated cause",
+			text:     "outer cause=[inner",
+			expected: "outer\n  cause: [inner",
+		},
+		{
+			name:     "malformed causes",
+			text:     "outer causes=[x]",
+			expected: "outer\n  causes=[x]",
+		},
+		{
+			name:     "unterminated quoted message",
+			text:     `"outer`,
+			expected: `"outer`,
+		},
+		{
+			name:     "text after quoted message",
+			text:     `"outer"x`,
+			expected: `"outer"x`,
+		},
+		{
+			name:     "legacy message starting with a quote",
+			text:     `"quoted" msg`,
+			expected: `"quoted" msg`,
+		},
+		{
+			name:     "quoted message",
+			text:     `"quoted msg" k=v`,
+			expected: "quoted msg\n  k=v",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.text, err)
+			}
+
+			if actual := fmt.Sprintf("%+v", parsed); actual != tt.expected {
+				t.Errorf("Parse(%q) =\n%s\nwant\n%s", tt.text, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTextAttr(t *testing.T) {
+	text := textAttr(strings.Repeat("a", 31) + "é and more").Value.String()
+	if text != strings.Repeat("a", 31)+"..." {
+		t.Errorf("textAttr() = %q, want the text cut before the multi-byte rune", text)
+	}
+}
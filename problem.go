@@ -0,0 +1,302 @@
+package serrors
+
+import (
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem details.
+const ProblemContentType = "application/problem+json"
+
+var (
+	// ProblemTypePrefix is prepended to the code of an error to form the
+	// type URI of its problem details.
+	ProblemTypePrefix = "urn:problem-type:"
+	// StatusKey is the key of the attribute that sets the HTTP status of an
+	// error, overriding the status registered for its code.
+	StatusKey = "status"
+	// InstanceKey is the key of the attribute used as the instance URI of
+	// problem details.
+	InstanceKey = "instance"
+)
+
+// ProblemDetails is an RFC 9457 problem details document.
+type ProblemDetails struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Extensions holds the extension members.
+	Extensions map[string]any `json:"-"`
+}
+
+// problemMembers are the standard members of problem details.
+var problemMembers = []string{"type", "title", "status", "detail", "instance"}
+
+// MarshalJSON implements json.Marshaler, inlining the extension members.
+// Extension members named like standard members are left out.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	type standard ProblemDetails
+
+	data, err := json.Marshal(standard(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+
+	b := data[:len(data)-1]
+	for _, key := range slices.Sorted(maps.Keys(p.Extensions)) {
+		if slices.Contains(problemMembers, key) {
+			continue
+		}
+
+		if len(b) > 1 {
+			b = append(b, ',')
+		}
+
+		value, err := json.Marshal(p.Extensions[key])
+		if err != nil {
+			return nil, err
+		}
+
+		b = appendJSONString(b, key)
+		b = append(b, ':')
+		b = append(b, value...)
+	}
+
+	return append(b, '}'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting the extension
+// members.
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	type standard ProblemDetails
+
+	var s standard
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	for _, key := range problemMembers {
+		delete(members, key)
+	}
+
+	*p = ProblemDetails(s)
+	if len(members) > 0 {
+		p.Extensions = members
+	}
+
+	return nil
+}
+
+// Problem converts err to problem details. The type is derived from the code
+// of err, and the status from its StatusKey attribute or the status
+// registered for its code, defaulting to 500. The title is the registered
+// description of the code, or the status text. The attributes with the given
+// keys, found anywhere in the chain, become extension members, along with
+// the code. Keys of standard members are ignored.
+//
+// Errors received from other services, as a [*RemoteError], are left out
+// when looking for the code and status, since they describe the responses
+// of those services. StatusKey attributes that are not between 400 and 599
+// are ignored.
+func Problem(err error, extensionKeys ...string) ProblemDetails {
+	p := ProblemDetails{
+		Type:   "about:blank",
+		Status: http.StatusInternalServerError,
+		Detail: err.Error(),
+	}
+
+	if s, ok := asSerror(err); ok {
+		p.Detail = s.Message()
+	}
+
+	code := ownCode(err)
+	info, registered := LookupCode(code)
+
+	if status, ok := ownStatus(err); ok && errorStatus(status) {
+		p.Status = int(status)
+	} else if status := registeredStatus(err); status != 0 {
+		p.Status = status
+	}
+
+	if code != "" {
+		p.Type = ProblemTypePrefix + code
+		p.Extensions = map[string]any{CodeKey: code}
+	}
+
+	if registered && info.Description != "" {
+		p.Title = info.Description
+	} else {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	if instance, ok := LookupString(err, InstanceKey); ok {
+		p.Instance = instance
+	}
+
+	for _, key := range extensionKeys {
+		if slices.Contains(problemMembers, key) {
+			continue
+		}
+
+		if value, ok := Lookup(err, key); ok {
+			if p.Extensions == nil {
+				p.Extensions = map[string]any{}
+			}
+
+			p.Extensions[key] = jsonAny(value)
+		}
+	}
+
+	return p
+}
+
+// FromProblem builds an error from an application/problem+json document. The
+// message is the detail, or the title. The code is taken from the code
+// extension member, or derived from the type. The status is recorded in a
+// group under HTTPKey, and the instance and the other extension members
+// become attributes. The result is a [*RemoteError] with an empty Service,
+// so that [Problem] does not take its code and status for those of errors
+// wrapping it.
+func FromProblem(data []byte) (error, error) {
+	members, err := decodeObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var p ProblemDetails
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+
+	s := serror{msg: p.Detail}
+	if s.msg == "" {
+		s.msg = p.Title
+	}
+
+	if code, ok := strings.CutPrefix(p.Type, ProblemTypePrefix); ok {
+		s.code = code
+	} else if p.Type != "" && p.Type != "about:blank" {
+		s.attrs = append(s.attrs, slog.String("type", p.Type))
+	}
+
+	if p.Status != 0 {
+		s.attrs = append(s.attrs, slog.Group(HTTPKey, slog.Int("status", p.Status)))
+	}
+
+	if p.Instance != "" {
+		s.attrs = append(s.attrs, slog.String(InstanceKey, p.Instance))
+	}
+
+	for _, m := range members {
+		ext, ok := p.Extensions[m.key]
+		if !ok {
+			continue
+		}
+
+		if m.key == CodeKey {
+			if code, ok := ext.(string); ok {
+				s.code = code
+				continue
+			}
+		}
+
+		value, err := decodeValue(m.value, m.key, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		s.attrs = append(s.attrs, slog.Attr{Key: m.key, Value: value})
+	}
+
+	return &RemoteError{err: s}, nil
+}
+
+// ownStatus returns the value of the first StatusKey attribute in the chain
+// of err, outermost first, leaving out errors received from other services.
+func ownStatus(err error) (int64, bool) {
+	var (
+		status int64
+		found  bool
+	)
+
+	walkLocal(err, func(s serror) bool {
+		status, found = LookupInt64(serror{attrs: s.attrs}, StatusKey)
+		return !found
+	})
+
+	return status, found
+}
+
+// ownCode is like CodeOf, but leaves out errors received from other
+// services.
+func ownCode(err error) string {
+	var code string
+
+	walkLocal(err, func(s serror) bool {
+		code = s.code
+		return code == ""
+	})
+
+	return code
+}
+
+// errorStatus reports whether status is an HTTP client or server error
+// status.
+func errorStatus(status int64) bool {
+	return status >= 400 && status <= 599
+}
+
+// registeredStatus returns the status registered for the outermost code in
+// the chain of err that has one, leaving out errors received from other
+// services.
+func registeredStatus(err error) int {
+	var status int
+
+	walkLocal(err, func(s serror) bool {
+		if info, ok := LookupCode(s.code); ok {
+			status = info.Status
+		}
+
+		return status == 0
+	})
+
+	return status
+}
+
+// walkLocal is like walk, but does not descend into errors received from
+// other services.
+func walkLocal(err error, fn func(s serror) bool) bool {
+	if _, remote := err.(*RemoteError); remote || err == nil {
+		return true
+	}
+
+	if s, ok := asSerror(err); ok && !fn(s) {
+		return false
+	}
+
+	for _, cause := range unwrapAll(err) {
+		if !walkLocal(cause, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jsonAny converts a logged value to a value encoded by encoding/json the
+// same way.
+func jsonAny(v slog.Value) any {
+	return json.RawMessage(appendJSONValue(nil, v))
+}
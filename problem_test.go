@@ -0,0 +1,255 @@
+package serrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+)
+
+var testCodeConflict = Register(CodeInfo{Code: "test.conflict", Description: "The resource already exists", Status: http.StatusConflict})
+
+func TestProblem(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		keys     []string
+		expected string
+	}{
+		{
+			name:     "standard error",
+			err:      errors.New("boom"),
+			expected: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"boom"}`,
+		},
+		{
+			name: "registered code",
+			err: WrapError("create user failed",
+				NewError("duplicate key", Code(testCodeConflict), slog.String("user_id", "123"))),
+			keys:     []string{"user_id", "missing"},
+			expected: `{"type":"urn:problem-type:test.conflict","title":"The resource already exists","status":409,"detail":"create user failed","code":"test.conflict","user_id":"123"}`,
+		},
+		{
+			name: "status attribute overrides code",
+			err: fmt.Errorf("handler: %w", NewError("no such user", Code(testCodeNotFound),
+				slog.Int(StatusKey, http.StatusNotFound), slog.String(InstanceKey, "/users/123"))),
+			expected: `{"type":"urn:problem-type:test.not_found","title":"The resource does not exist","status":404,"detail":"handler: no such user status=404 instance=/users/123","instance":"/users/123","code":"test.not_found"}`,
+		},
+		{
+			name:     "status attribute out of range",
+			err:      NewError("created", Code(testCodeConflict), slog.Int(StatusKey, http.StatusCreated)),
+			expected: `{"type":"urn:problem-type:test.conflict","title":"The resource already exists","status":409,"detail":"created","code":"test.conflict"}`,
+		},
+		{
+			name:     "standard member keys",
+			err:      NewError("bad", slog.Int(StatusKey, http.StatusTeapot), slog.String("title", "T")),
+			keys:     []string{"title", "status", "type", "detail", "instance"},
+			expected: `{"type":"about:blank","title":"I'm a teapot","status":418,"detail":"bad"}`,
+		},
+		{
+			name:     "group extension",
+			err:      NewError("invalid input", slog.Int(StatusKey, http.StatusBadRequest), slog.Group("field", slog.String("name", "email"))),
+			keys:     []string{"field"},
+			expected: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid input","field":{"name":"email"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(Problem(tt.err, tt.keys...))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+
+			if actual := string(data); actual != tt.expected {
+				t.Errorf("Problem() = %s, want %s", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFromProblem(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		code     string
+		expected string
+	}{
+		{
+			name:     "round trip",
+			data:     `{"type":"urn:problem-type:test.conflict","title":"The resource already exists","status":409,"detail":"create user failed","instance":"/users/123","code":"test.conflict","user_id":"123","retry":2}`,
+			code:     testCodeConflict,
+			expected: "create user failed http=[status=409] instance=/users/123 user_id=123 retry=2",
+		},
+		{
+			name:     "foreign type",
+			data:     `{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.","status":403,"balance":30}`,
+			expected: "You do not have enough credit. type=https://example.com/probs/out-of-credit http=[status=403] balance=30",
+		},
+		{
+			name:     "code member overrides type",
+			data:     `{"type":"urn:problem-type:other","detail":"gone","code":"test.gone"}`,
+			code:     "test.gone",
+			expected: "gone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err, decodeErr := FromProblem([]byte(tt.data))
+			if decodeErr != nil {
+				t.Fatalf("FromProblem() error = %v", decodeErr)
+			}
+
+			if actual := err.Error(); actual != tt.expected {
+				t.Errorf("Error() = %q, want %q", actual, tt.expected)
+			}
+
+			if actual := CodeOf(err); actual != tt.code {
+				t.Errorf("CodeOf() = %q, want %q", actual, tt.code)
+			}
+		})
+	}
+}
+
+func TestProblem_DownstreamStatus(t *testing.T) {
+	decoded, err := FromProblem([]byte(`{"title":"Unauthorized","status":401,"detail":"token expired"}`))
+	if err != nil {
+		t.Fatalf("FromProblem() error = %v", err)
+	}
+
+	data, err := MarshalRemote("users", NewError("token expired", slog.Int(StatusKey, http.StatusUnauthorized)))
+	if err != nil {
+		t.Fatalf("MarshalRemote() error = %v", err)
+	}
+
+	remote, err := UnmarshalRemote(data)
+	if err != nil {
+		t.Fatalf("UnmarshalRemote() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{
+			name:     "problem details",
+			err:      WrapError("fetch profile", decoded),
+			expected: http.StatusInternalServerError,
+		},
+		{
+			name:     "remote error",
+			err:      WrapError("fetch profile", remote),
+			expected: http.StatusInternalServerError,
+		},
+		{
+			name:     "own status",
+			err:      WrapError("fetch profile", remote, slog.Int(StatusKey, http.StatusBadGateway)),
+			expected: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := Problem(tt.err).Status; actual != tt.expected {
+				t.Errorf("Status = %d, want %d", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProblem_DownstreamCode(t *testing.T) {
+	decoded, err := FromProblem([]byte(`{"type":"urn:problem-type:test.conflict","status":409,"detail":"user exists"}`))
+	if err != nil {
+		t.Fatalf("FromProblem() error = %v", err)
+	}
+
+	data, err := MarshalRemote("users", NewError("user exists", Code(testCodeConflict)))
+	if err != nil {
+		t.Fatalf("MarshalRemote() error = %v", err)
+	}
+
+	remote, err := UnmarshalRemote(data)
+	if err != nil {
+		t.Fatalf("UnmarshalRemote() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		typ    string
+	}{
+		{
+			name:   "problem details",
+			err:    WrapError("create profile", decoded),
+			status: http.StatusInternalServerError,
+			typ:    "about:blank",
+		},
+		{
+			name:   "remote error",
+			err:    fmt.Errorf("create profile: %w", remote),
+			status: http.StatusInternalServerError,
+			typ:    "about:blank",
+		},
+		{
+			name:   "own code",
+			err:    WrapError("create profile", remote, Code(testCodeNotFound), slog.Int(StatusKey, http.StatusNotFound)),
+			status: http.StatusNotFound,
+			typ:    ProblemTypePrefix + testCodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Problem(tt.err)
+			if p.Status != tt.status {
+				t.Errorf("Status = %d, want %d", p.Status, tt.status)
+			}
+			if p.Type != tt.typ {
+				t.Errorf("Type = %q, want %q", p.Type, tt.typ)
+			}
+		})
+	}
+}
+
+func TestFromProblem_Invalid(t *testing.T) {
+	if _, err := FromProblem([]byte(`{"status":"teapot"}`)); err == nil {
+		t.Error("FromProblem() error = nil, want error")
+	}
+}
+
+func TestProblemDetails_MarshalJSON_StandardExtensions(t *testing.T) {
+	p := ProblemDetails{
+		Type:       "about:blank",
+		Status:     http.StatusBadRequest,
+		Extensions: map[string]any{"status": 200, "title": "T", "field": "email"},
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	expected := `{"type":"about:blank","status":400,"field":"email"}`
+	if string(data) != expected {
+		t.Errorf("json.Marshal() = %s, want %s", data, expected)
+	}
+}
+
+func TestProblemDetails_UnmarshalJSON(t *testing.T) {
+	var p ProblemDetails
+	if err := json.Unmarshal([]byte(`{"type":"about:blank","status":400,"errors":["a"]}`), &p); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if p.Status != http.StatusBadRequest || p.Type != "about:blank" {
+		t.Errorf("ProblemDetails = %+v", p)
+	}
+
+	if _, ok := p.Extensions["errors"]; !ok || len(p.Extensions) != 1 {
+		t.Errorf("Extensions = %v, want only errors", p.Extensions)
+	}
+}
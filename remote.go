@@ -0,0 +1,127 @@
+package serrors
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+)
+
+var (
+	// RemoteHeader is the suggested HTTP header, or gRPC metadata key once
+	// lowercased, for errors encoded with EncodeHeader.
+	RemoteHeader = "Serrors-Error"
+	// ServiceKey is the key under which the originating service of a
+	// RemoteError is logged.
+	ServiceKey = "service"
+)
+
+// remoteVersion is the version of the wire format.
+const remoteVersion = 1
+
+// RemoteError is an error received from another service. It unwraps to the
+// decoded chain, so its message, attributes and codes are available as for
+// local errors, and [errors.Is] matches definitions that have the same code.
+// Use [errors.As] with a *RemoteError to tell remote errors from local ones.
+type RemoteError struct {
+	// Service is the name of the service that produced the error, or empty
+	// if it is unknown.
+	Service string
+
+	err error
+}
+
+// Error implements error.
+func (r *RemoteError) Error() string {
+	return r.err.Error()
+}
+
+func (r *RemoteError) Unwrap() error {
+	return r.err
+}
+
+func (r *RemoteError) LogValue() slog.Value {
+	value := causeValue(r.err).Resolve()
+	if value.Kind() != slog.KindGroup {
+		value = slog.GroupValue(slog.String(slog.MessageKey, r.err.Error()))
+	}
+
+	if r.Service == "" {
+		return value
+	}
+
+	attrs := append([]slog.Attr{slog.String(ServiceKey, r.Service)}, value.Group()...)
+
+	return slog.GroupValue(attrs...)
+}
+
+type remoteEnvelope struct {
+	Version int             `json:"v"`
+	Service string          `json:"service"`
+	Error   json.RawMessage `json:"error"`
+}
+
+// MarshalRemote encodes the whole chain of err, as produced by service, for
+// another process. If err is a *RemoteError, its original service is kept.
+// Sources and stack traces are left out, so that the encoding stays small
+// and internal file paths are not disclosed.
+func MarshalRemote(service string, err error) ([]byte, error) {
+	if err == nil {
+		return nil, errors.New("serrors: cannot encode a nil error")
+	}
+
+	if remote, ok := err.(*RemoteError); ok {
+		service, err = remote.Service, remote.err
+	}
+
+	return json.Marshal(remoteEnvelope{
+		Version: remoteVersion,
+		Service: service,
+		Error:   appendJSONError(nil, err, false),
+	})
+}
+
+// UnmarshalRemote decodes an error encoded with MarshalRemote.
+func UnmarshalRemote(data []byte) (*RemoteError, error) {
+	var envelope remoteEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	if envelope.Version != remoteVersion {
+		return nil, NewError("serrors: unsupported remote error version",
+			slog.Int("version", envelope.Version))
+	}
+
+	if len(envelope.Error) == 0 {
+		return nil, errors.New("serrors: remote error without an error")
+	}
+
+	err, decodeErr := decodeCause(envelope.Error)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	return &RemoteError{Service: envelope.Service, err: err}, nil
+}
+
+// EncodeHeader encodes err like MarshalRemote, in a compact form that can be
+// used as an HTTP header or gRPC metadata value.
+func EncodeHeader(service string, err error) (string, error) {
+	data, marshalErr := MarshalRemote(service, err)
+	if marshalErr != nil {
+		return "", marshalErr
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeHeader decodes an error encoded with EncodeHeader.
+func DecodeHeader(value string) (*RemoteError, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalRemote(data)
+}
@@ -0,0 +1,183 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+var errTestRemoteNotFound = Define("not found", Code("test.remote.not_found"))
+
+func TestRemoteError_RoundTrip(t *testing.T) {
+	original := WrapError("fetch user failed",
+		errTestRemoteNotFound.New(slog.String("user_id", "123")),
+		slog.Int("retry", 2))
+
+	tests := []struct {
+		name   string
+		decode func(t *testing.T) (*RemoteError, error)
+	}{
+		{
+			name: "JSON",
+			decode: func(t *testing.T) (*RemoteError, error) {
+				data, err := MarshalRemote("users", original)
+				if err != nil {
+					t.Fatalf("MarshalRemote() error = %v", err)
+				}
+
+				return UnmarshalRemote(data)
+			},
+		},
+		{
+			name: "header",
+			decode: func(t *testing.T) (*RemoteError, error) {
+				value, err := EncodeHeader("users", original)
+				if err != nil {
+					t.Fatalf("EncodeHeader() error = %v", err)
+				}
+				if strings.ContainsAny(value, " \n\r\t:\"") {
+					t.Errorf("Header value is not compact: %q", value)
+				}
+
+				return DecodeHeader(value)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote, err := tt.decode(t)
+			if err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+
+			if remote.Service != "users" {
+				t.Errorf("Service = %q, want users", remote.Service)
+			}
+			if remote.Error() != original.Error() {
+				t.Errorf("Error() = %q, want %q", remote.Error(), original.Error())
+			}
+			if !errors.Is(remote, errTestRemoteNotFound) {
+				t.Errorf("Expected errors.Is to match the definition by code")
+			}
+			if CodeOf(remote) != "test.remote.not_found" {
+				t.Errorf("CodeOf() = %q", CodeOf(remote))
+			}
+			if v, ok := LookupInt64(remote, "retry"); !ok || v != 2 {
+				t.Errorf("LookupInt64(retry) = %d, %v", v, ok)
+			}
+
+			local := fmt.Errorf("handler: %w", remote)
+			var target *RemoteError
+			if !errors.As(local, &target) || target.Service != "users" {
+				t.Errorf("Expected errors.As to find the RemoteError")
+			}
+			if errors.As(errTestRemoteNotFound.New(), &target) {
+				t.Errorf("A local error should not be a RemoteError")
+			}
+		})
+	}
+}
+
+func TestEncodeHeader_NoLocation(t *testing.T) {
+	setStackCapture(t, StackAll)
+	setCaptureSource(t, true, false)
+
+	err := WrapError("fetch user failed",
+		WrapError("query failed", errTestRemoteNotFound.New(slog.String("user_id", "123"))),
+		slog.Int("retry", 2))
+
+	value, encodeErr := EncodeHeader("users", err)
+	if encodeErr != nil {
+		t.Fatalf("EncodeHeader() error = %v", encodeErr)
+	}
+
+	if len(value) > 512 {
+		t.Errorf("Header value has %d bytes, want at most 512", len(value))
+	}
+
+	remote, decodeErr := DecodeHeader(value)
+	if decodeErr != nil {
+		t.Fatalf("DecodeHeader() error = %v", decodeErr)
+	}
+
+	if StackTrace(remote) != nil {
+		t.Errorf("StackTrace() = %v, want nil", StackTrace(remote))
+	}
+	if Source(remote) != nil {
+		t.Errorf("Source() = %v, want nil", Source(remote))
+	}
+}
+
+func TestRemoteError_Forward(t *testing.T) {
+	data, err := MarshalRemote("users", errors.New("database down"))
+	if err != nil {
+		t.Fatalf("MarshalRemote() error = %v", err)
+	}
+
+	remote, err := UnmarshalRemote(data)
+	if err != nil {
+		t.Fatalf("UnmarshalRemote() error = %v", err)
+	}
+	if remote.Error() != "database down" {
+		t.Errorf("Error() = %q", remote.Error())
+	}
+
+	data, err = MarshalRemote("gateway", remote)
+	if err != nil {
+		t.Fatalf("MarshalRemote() error = %v", err)
+	}
+
+	forwarded, err := UnmarshalRemote(data)
+	if err != nil {
+		t.Fatalf("UnmarshalRemote() error = %v", err)
+	}
+	if forwarded.Service != "users" {
+		t.Errorf("Service = %q, want the originating service", forwarded.Service)
+	}
+}
+
+func TestRemoteError_LogValue(t *testing.T) {
+	data, _ := MarshalRemote("users", NewError("not found", slog.String("user_id", "123")))
+	remote, err := UnmarshalRemote(data)
+	if err != nil {
+		t.Fatalf("UnmarshalRemote() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "error", remote)
+
+	var logOutput map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &logOutput); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	errorGroup, _ := logOutput["error"].(map[string]any)
+	if errorGroup[ServiceKey] != "users" || errorGroup["msg"] != "not found" || errorGroup["user_id"] != "123" {
+		t.Errorf("error = %v", logOutput["error"])
+	}
+}
+
+func TestUnmarshalRemote_Errors(t *testing.T) {
+	for _, data := range []string{
+		`{"v":2,"service":"users","error":"x"}`,
+		`{"v":1,"service":"users"}`,
+		`{"v":1,"service":"users","error":{"msg":1}}`,
+		`not json`,
+	} {
+		if _, err := UnmarshalRemote([]byte(data)); err == nil {
+			t.Errorf("UnmarshalRemote(%s) should fail", data)
+		}
+	}
+
+	if _, err := DecodeHeader("!!!"); err == nil {
+		t.Errorf("DecodeHeader() should fail on invalid base64")
+	}
+	if _, err := MarshalRemote("users", nil); err == nil {
+		t.Errorf("MarshalRemote() should fail on a nil error")
+	}
+}
@@ -0,0 +1,166 @@
+package serrors
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rendering selects the format of the text returned by Error.
+type Rendering int
+
+const (
+	// RenderV1 is the original format. Attributes are rendered with
+	// [slog.Attr.String] and causes are framed as cause=[...], without any
+	// quoting, so values containing spaces, '=' or ']' are ambiguous.
+	RenderV1 Rendering = iota
+	// RenderV2 follows the quoting rules of [slog.TextHandler]: keys and
+	// values are quoted when needed, as are values starting with '[', groups
+	// are flattened into dotted keys and causes are rendered as quoted
+	// values, e.g.
+	//
+	//	fetch failed cause="timeout after=5s" input="hello world"
+	//
+	// It is the recommended format for new code.
+	RenderV2
+)
+
+// ErrorRendering is the format used by Error. It defaults to RenderV1 so that
+// existing output stays stable.
+var ErrorRendering = RenderV1
+
+// quotedError renders s in the RenderV2 format.
+func (s serror) quotedError() string {
+	var b strings.Builder
+
+	msg := s.Message()
+	if msgNeedsQuoting(msg) {
+		msg = strconv.Quote(msg)
+	}
+
+	_, _ = b.WriteString(msg)
+
+	if s.err != nil && !s.inline {
+		writeQuotedAttr(&b, CauseKey, s.err.Error())
+	}
+
+	if !s.inline {
+		for i, err := range s.errs {
+			writeQuotedAttr(&b, CausesKey+"."+strconv.Itoa(i), err.Error())
+		}
+	}
+
+	for _, attr := range s.attrs {
+		writeQuotedAttrs(&b, "", attr)
+	}
+
+	if SourceInError && s.hasSource() {
+		writeQuotedAttr(&b, slog.SourceKey, sourceString(s.Source()))
+	}
+
+	return b.String()
+}
+
+// writeQuotedAttrs writes attr, flattening groups into dotted keys.
+func writeQuotedAttrs(b *strings.Builder, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+
+		for _, a := range value.Group() {
+			writeQuotedAttrs(b, prefix, a)
+		}
+
+		return
+	}
+
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	writeQuotedAttr(b, prefix+attr.Key, textValue(value))
+}
+
+func writeQuotedAttr(b *strings.Builder, key, value string) {
+	_ = b.WriteByte(' ')
+	_, _ = b.WriteString(quoteIfNeeded(key))
+	_ = b.WriteByte('=')
+	_, _ = b.WriteString(quoteIfNeeded(value))
+}
+
+// textValue renders v the way [slog.TextHandler] does, before quoting.
+func textValue(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format("2006-01-02T15:04:05.000Z07:00")
+	case slog.KindAny:
+		switch a := v.Any().(type) {
+		case error:
+			return a.Error()
+		case encoding.TextMarshaler:
+			data, err := a.MarshalText()
+			if err != nil {
+				return "!ERROR:" + err.Error()
+			}
+
+			return string(data)
+		case []byte:
+			return string(a)
+		default:
+			return fmt.Sprintf("%+v", a)
+		}
+	case slog.KindDuration:
+		return v.Duration().String()
+	default:
+		return v.String()
+	}
+}
+
+func quoteIfNeeded(s string) string {
+	if needsQuoting(s) {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// needsQuoting reports whether s must be quoted as a key or value, following
+// the rules of slog.TextHandler. Text starting with '[' is quoted as well, so
+// that it cannot be taken for the bracket framing of RenderV1.
+func needsQuoting(s string) bool {
+	if len(s) == 0 || s[0] == '[' {
+		return true
+	}
+
+	for _, r := range s {
+		if forcesQuoting(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// msgNeedsQuoting is like needsQuoting, but allows spaces and empty
+// messages, which cannot be confused with attributes.
+func msgNeedsQuoting(s string) bool {
+	for _, r := range s {
+		if r != ' ' && forcesQuoting(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forcesQuoting reports whether a value containing r must be quoted.
+func forcesQuoting(r rune) bool {
+	return r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) || r == utf8.RuneError
+}
@@ -0,0 +1,167 @@
+package serrors
+
+import (
+	"errors"
+	"log/slog"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func setErrorRendering(t *testing.T, rendering Rendering) {
+	t.Helper()
+
+	previous := ErrorRendering
+	ErrorRendering = rendering
+	t.Cleanup(func() { ErrorRendering = previous })
+}
+
+func TestSerror_Error_RenderV2(t *testing.T) {
+	tests := []struct {
+		name     string
+		create   func() error
+		expected string
+	}{
+		{
+			name: "message only",
+			create: func() error {
+				return NewError("user not found")
+			},
+			expected: "user not found",
+		},
+		{
+			name: "plain values are not quoted",
+			create: func() error {
+				return NewError("user not found", slog.String("user_id", "123"), slog.Int("retry", 3), slog.Bool("ok", false))
+			},
+			expected: "user not found user_id=123 retry=3 ok=false",
+		},
+		{
+			name: "values with spaces and special characters",
+			create: func() error {
+				return NewError("parse error",
+					slog.String("input", "hello world"),
+					slog.String("chars", "[]{}="),
+					slog.String("quoted", `"hello"`),
+					slog.String("multiline", "value1\nvalue2"),
+					slog.String("empty", ""),
+					slog.String("unicode", "café"))
+			},
+			expected: `parse error input="hello world" chars="[]{}=" quoted="\"hello\"" multiline="value1\nvalue2" empty="" unicode=café`,
+		},
+		{
+			name: "keys needing quotes",
+			create: func() error {
+				return NewError("test error", slog.String("my key", "v"))
+			},
+			expected: `test error "my key"=v`,
+		},
+		{
+			name: "message needing quotes",
+			create: func() error {
+				return NewError("bad a=b \"x\"\nline", slog.String("k", "v"))
+			},
+			expected: `"bad a=b \"x\"\nline" k=v`,
+		},
+		{
+			name: "message with brackets",
+			create: func() error {
+				return NewError("index [3] out of range")
+			},
+			expected: "index [3] out of range",
+		},
+		{
+			name: "values starting with a bracket",
+			create: func() error {
+				return WrapError("fetch", errors.New("[eof]"), slog.String("tag", "[x"), slog.String("index", "a[0]"))
+			},
+			expected: `fetch cause="[eof]" tag="[x" index=a[0]`,
+		},
+		{
+			name: "standard cause",
+			create: func() error {
+				return WrapError("fetch failed", errors.New("connection refused"))
+			},
+			expected: `fetch failed cause="connection refused"`,
+		},
+		{
+			name: "cause containing a bracket",
+			create: func() error {
+				return WrapError("fetch failed", errors.New("unexpected ]"), slog.String("k", "v"))
+			},
+			expected: `fetch failed cause="unexpected ]" k=v`,
+		},
+		{
+			name: "nested serrors",
+			create: func() error {
+				return WrapError("level 1",
+					WrapError("level 2", errors.New("level 3"), slog.String("input", "a b")),
+					slog.String("level", "1"))
+			},
+			expected: `level 1 cause="level 2 cause=\"level 3\" input=\"a b\"" level=1`,
+		},
+		{
+			name: "single word cause",
+			create: func() error {
+				return WrapError("read failed", errors.New("EOF"))
+			},
+			expected: "read failed cause=EOF",
+		},
+		{
+			name: "multiple causes",
+			create: func() error {
+				return WrapErrors("batch failed", []error{errors.New("a"), errors.New("b failed")})
+			},
+			expected: `batch failed causes.0=a causes.1="b failed"`,
+		},
+		{
+			name: "groups are flattened",
+			create: func() error {
+				return NewError("request failed", slog.Group("http",
+					slog.Int("status", 503),
+					slog.Group("request", slog.String("method", "GET"))))
+			},
+			expected: "request failed http.status=503 http.request.method=GET",
+		},
+		{
+			name: "other kinds",
+			create: func() error {
+				return NewError("test error",
+					slog.Duration("elapsed", 1500*time.Millisecond),
+					slog.Time("at", time.Date(2023, 1, 2, 3, 4, 5, 600000000, time.UTC)),
+					slog.Any("addr", netip.MustParseAddr("127.0.0.1")),
+					slog.Any("err", errors.New("bad thing")),
+					slog.Any("data", map[string]string{"key": "value"}))
+			},
+			expected: `test error elapsed=1.5s at=2023-01-02T03:04:05.600Z addr=127.0.0.1 err="bad thing" data=map[key:value]`,
+		},
+		{
+			name: "Errorf keeps its message",
+			create: func() error {
+				return Errorf("fetch %s: %w", "users", errors.New("timeout"), slog.String("input", "a b"))
+			},
+			expected: `fetch users: timeout input="a b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setErrorRendering(t, RenderV2)
+
+			if actual := tt.create().Error(); actual != tt.expected {
+				t.Errorf("Error() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSerror_Error_RenderV1Default(t *testing.T) {
+	if ErrorRendering != RenderV1 {
+		t.Fatalf("ErrorRendering = %v, want RenderV1", ErrorRendering)
+	}
+
+	err := NewError("parse error", slog.String("input", "hello world"))
+	if err.Error() != "parse error input=hello world" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
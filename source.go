@@ -0,0 +1,66 @@
+package serrors
+
+import (
+	"log/slog"
+	"runtime"
+	"strconv"
+)
+
+var (
+	// CaptureSource records the caller of the constructors as a
+	// [slog.Source], logged under [slog.SourceKey].
+	CaptureSource = false
+	// SourceInError appends the captured source as file:line to the output
+	// of Error.
+	SourceInError = false
+)
+
+// Source returns the caller recorded for the outermost error in the chain of
+// err that has one, or nil.
+func Source(err error) *slog.Source {
+	var src *slog.Source
+
+	walk(err, func(s serror, _ int) bool {
+		src = s.Source()
+		return src == nil
+	})
+
+	return src
+}
+
+// captureCaller records the program counter of the caller, skipping skip
+// additional frames.
+func captureCaller(skip int) uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return 0
+	}
+
+	return pcs[0]
+}
+
+func pcSource(pc uintptr) *slog.Source {
+	if pc == 0 {
+		return nil
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	return &slog.Source{
+		Function: frame.Function,
+		File:     frame.File,
+		Line:     frame.Line,
+	}
+}
+
+func sourceAttr(src *slog.Source) slog.Attr {
+	return slog.Group(slog.SourceKey,
+		slog.String("function", src.Function),
+		slog.String("file", src.File),
+		slog.Int("line", src.Line),
+	)
+}
+
+func sourceString(src *slog.Source) string {
+	return src.File + ":" + strconv.Itoa(src.Line)
+}
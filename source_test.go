@@ -0,0 +1,148 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func setCaptureSource(t *testing.T, capture, inError bool) {
+	t.Helper()
+
+	previousCapture, previousInError := CaptureSource, SourceInError
+	CaptureSource, SourceInError = capture, inError
+	t.Cleanup(func() { CaptureSource, SourceInError = previousCapture, previousInError })
+}
+
+func newHelperError(msg string) error {
+	return NewError(msg, CallerSkip(1), slog.String("helper", "yes"))
+}
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name             string
+		create           func() error
+		expectedFunction string
+	}{
+		{
+			name: "NewError",
+			create: func() error {
+				return NewError("test error")
+			},
+			expectedFunction: "TestSource.func1",
+		},
+		{
+			name: "WrapError",
+			create: func() error {
+				return WrapError("test error", errors.New("cause"))
+			},
+			expectedFunction: "TestSource.func2",
+		},
+		{
+			name: "helper with CallerSkip",
+			create: func() error {
+				return newHelperError("test error")
+			},
+			expectedFunction: "TestSource.func3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setCaptureSource(t, true, false)
+
+			src := Source(tt.create())
+			if src == nil {
+				t.Fatalf("Expected a source to be recorded")
+			}
+			if !strings.HasSuffix(src.Function, tt.expectedFunction) {
+				t.Errorf("Source().Function = %q, want suffix %q", src.Function, tt.expectedFunction)
+			}
+			if !strings.HasSuffix(src.File, "source_test.go") {
+				t.Errorf("Source().File = %q, want source_test.go", src.File)
+			}
+			if src.Line == 0 {
+				t.Errorf("Source().Line should not be 0")
+			}
+		})
+	}
+}
+
+func TestSource_Disabled(t *testing.T) {
+	setCaptureSource(t, false, true)
+
+	err := NewError("test error")
+	if Source(err) != nil {
+		t.Errorf("Expected no source when capture is disabled")
+	}
+	if err.Error() != "test error" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "test error")
+	}
+}
+
+func TestSource_Error(t *testing.T) {
+	setCaptureSource(t, true, true)
+
+	err := NewError("test error", slog.String("key", "value"))
+	src := Source(err)
+
+	expected := fmt.Sprintf("test error key=value source=%s:%d", src.File, src.Line)
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestSource_LogValue(t *testing.T) {
+	setCaptureSource(t, true, false)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	logger.Error("operation failed", "error", newHelperError("test error"))
+
+	var logOutput map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &logOutput); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	errorGroup, ok := logOutput["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected 'error' to be a group, got %T", logOutput["error"])
+	}
+
+	if _, exists := errorGroup[""]; exists {
+		t.Errorf("Options should not be logged as attributes")
+	}
+	if errorGroup["helper"] != "yes" {
+		t.Errorf("Expected helper 'yes', got %v", errorGroup["helper"])
+	}
+
+	source, ok := errorGroup[slog.SourceKey].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected '%s' to be a group, got %T", slog.SourceKey, errorGroup[slog.SourceKey])
+	}
+	for _, key := range []string{"function", "file", "line"} {
+		if _, exists := source[key]; !exists {
+			t.Errorf("Expected source key '%s' not found", key)
+		}
+	}
+	if !strings.HasSuffix(source["function"].(string), "TestSource_LogValue") {
+		t.Errorf("Unexpected source function %v", source["function"])
+	}
+}
+
+func TestCallerSkip_Attrs(t *testing.T) {
+	attrs := []slog.Attr{slog.String("a", "1"), CallerSkip(1), slog.String("b", "2")}
+	err := NewError("test error", attrs...)
+
+	if err.Error() != "test error a=1 b=2" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "test error a=1 b=2")
+	}
+	if attrs[1].Key != "" || attrs[2].Key != "b" {
+		t.Errorf("Constructor modified the caller's attributes: %v", attrs)
+	}
+}
@@ -0,0 +1,121 @@
+package serrors
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// StackPolicy controls when stack traces are captured.
+type StackPolicy int
+
+const (
+	// StackNone disables stack capture.
+	StackNone StackPolicy = iota
+	// StackAll captures a stack for every error.
+	StackAll
+	// StackInnermost captures a stack only when no error in the wrapped
+	// chain already carries one, avoiding duplicate traces.
+	StackInnermost
+)
+
+var (
+	// StackCapture is the policy used by the constructors to decide whether
+	// to capture a stack trace.
+	StackCapture = StackNone
+	// StackKey is the key under which the stack trace is logged.
+	StackKey = "stack"
+	// StackDepth is the maximum number of frames captured.
+	StackDepth = 32
+)
+
+// StackTrace returns the innermost stack trace captured in the chain of err,
+// or nil if there is none.
+func StackTrace(err error) []runtime.Frame {
+	var st *stack
+
+	walk(err, func(s serror, _ int) bool {
+		if s.stack != nil {
+			st = s.stack
+		}
+
+		return true
+	})
+
+	if st == nil {
+		return nil
+	}
+
+	return st.frames()
+}
+
+// stack holds program counters, symbolized only when first needed.
+type stack struct {
+	pcs []uintptr
+
+	once    sync.Once
+	symbols []runtime.Frame
+}
+
+// captureStack records the stack of the caller, skipping skip additional
+// frames.
+func captureStack(skip int) *stack {
+	pcs := make([]uintptr, StackDepth)
+	n := runtime.Callers(skip+2, pcs)
+
+	return &stack{pcs: pcs[:n]}
+}
+
+func shouldCaptureStack(causes []error) bool {
+	switch StackCapture {
+	case StackAll:
+		return true
+	case StackInnermost:
+		for _, cause := range causes {
+			found := !walk(cause, func(s serror, _ int) bool {
+				return s.stack == nil
+			})
+			if found {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+func (st *stack) frames() []runtime.Frame {
+	st.once.Do(func() {
+		if len(st.pcs) == 0 {
+			return
+		}
+
+		frames := runtime.CallersFrames(st.pcs)
+		for {
+			frame, more := frames.Next()
+			st.symbols = append(st.symbols, frame)
+
+			if !more {
+				break
+			}
+		}
+	})
+
+	return st.symbols
+}
+
+func (st *stack) strings() []string {
+	return frameStrings(st.frames())
+}
+
+func frameStrings(frames []runtime.Frame) []string {
+	lines := make([]string, 0, len(frames))
+
+	for _, frame := range frames {
+		lines = append(lines, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+	}
+
+	return lines
+}
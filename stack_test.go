@@ -0,0 +1,123 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func setStackCapture(t *testing.T, policy StackPolicy) {
+	t.Helper()
+
+	previous := StackCapture
+	StackCapture = policy
+	t.Cleanup(func() { StackCapture = previous })
+}
+
+func TestStackCapture_Policies(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        StackPolicy
+		expectedOuter bool
+		expectedInner bool
+	}{
+		{
+			name:          "none",
+			policy:        StackNone,
+			expectedOuter: false,
+			expectedInner: false,
+		},
+		{
+			name:          "all",
+			policy:        StackAll,
+			expectedOuter: true,
+			expectedInner: true,
+		},
+		{
+			name:          "innermost",
+			policy:        StackInnermost,
+			expectedOuter: false,
+			expectedInner: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setStackCapture(t, tt.policy)
+
+			inner := NewError("inner")
+			outer := WrapError("outer", fmt.Errorf("middle: %w", inner))
+
+			if actual := outer.(Error).StackTrace() != nil; actual != tt.expectedOuter {
+				t.Errorf("outer has stack = %v, want %v", actual, tt.expectedOuter)
+			}
+			if actual := inner.(Error).StackTrace() != nil; actual != tt.expectedInner {
+				t.Errorf("inner has stack = %v, want %v", actual, tt.expectedInner)
+			}
+		})
+	}
+}
+
+func TestStackCapture_Innermost_StandardCause(t *testing.T) {
+	setStackCapture(t, StackInnermost)
+
+	err := WrapError("outer", errors.New("plain"))
+	if err.(Error).StackTrace() == nil {
+		t.Errorf("Expected a stack when the chain has no other stack")
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	setStackCapture(t, StackAll)
+
+	err := fmt.Errorf("handler: %w", WrapError("outer", NewError("inner")))
+
+	frames := StackTrace(err)
+	if len(frames) == 0 {
+		t.Fatalf("Expected a stack trace")
+	}
+
+	if !strings.HasSuffix(frames[0].Function, "TestStackTrace") {
+		t.Errorf("Expected first frame in TestStackTrace, got %s", frames[0].Function)
+	}
+	if !strings.HasSuffix(frames[0].File, "stack_test.go") {
+		t.Errorf("Expected first frame in stack_test.go, got %s", frames[0].File)
+	}
+
+	if StackTrace(errors.New("plain")) != nil {
+		t.Errorf("Expected no stack trace for a standard error")
+	}
+}
+
+func TestStackCapture_LogValue(t *testing.T) {
+	setStackCapture(t, StackAll)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	logger.Error("operation failed", "error", NewError("test error", slog.String("key", "value")))
+
+	var logOutput map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &logOutput); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	errorGroup, ok := logOutput["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected 'error' to be a group, got %T", logOutput["error"])
+	}
+
+	frames, ok := errorGroup[StackKey].([]any)
+	if !ok || len(frames) == 0 {
+		t.Fatalf("Expected '%s' to be a non-empty array, got %T", StackKey, errorGroup[StackKey])
+	}
+
+	first, _ := frames[0].(string)
+	if !strings.Contains(first, "TestStackCapture_LogValue") || !strings.Contains(first, "stack_test.go:") {
+		t.Errorf("Unexpected first frame: %q", first)
+	}
+}
@@ -0,0 +1,201 @@
+package serrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// causeList is the logged value of several causes. It is rendered as a JSON
+// array of structured causes, and as a bracketed list of their messages
+// otherwise.
+type causeList []error
+
+func (c causeList) String() string {
+	var b strings.Builder
+
+	_ = b.WriteByte('[')
+
+	for i, err := range c {
+		if i > 0 {
+			_ = b.WriteByte(' ')
+		}
+
+		_, _ = b.WriteString("[" + err.Error() + "]")
+	}
+
+	_ = b.WriteByte(']')
+
+	return b.String()
+}
+
+func (c causeList) MarshalJSON() ([]byte, error) {
+	b := []byte{'['}
+
+	for i, err := range c {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		b = appendJSONValue(b, causeValue(err))
+	}
+
+	return append(b, ']'), nil
+}
+
+// causeValue returns the nested logged value of a cause. Standard wrappers,
+// such as those created by [fmt.Errorf] and [errors.Join], are looked
+// through when they hold a structured error, so that its attributes are not
+// flattened into a string.
+func causeValue(err error) slog.Value {
+	if s, ok := asSerror(err); ok {
+		return s.nestedValue()
+	}
+
+	if _, ok := err.(slog.LogValuer); ok || !hasLogValuer(err) {
+		return slog.AnyValue(err)
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return slog.GroupValue(
+			slog.String(slog.MessageKey, err.Error()),
+			slog.Any(CauseKey, causeValue(e.Unwrap())),
+		)
+	case interface{ Unwrap() []error }:
+		errs := e.Unwrap()
+
+		// The message of errors.Join is made up entirely of its causes, so
+		// only they are logged.
+		if isJoin(err, errs) {
+			return slog.AnyValue(causeList(errs))
+		}
+
+		return slog.GroupValue(
+			slog.String(slog.MessageKey, err.Error()),
+			slog.Any(CausesKey, causeList(errs)),
+		)
+	default:
+		return slog.AnyValue(err)
+	}
+}
+
+// hasLogValuer reports whether the chain of err contains a
+// [slog.LogValuer].
+func hasLogValuer(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return false
+	case slog.LogValuer:
+		return true
+	case interface{ Unwrap() error }:
+		return hasLogValuer(e.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			if hasLogValuer(err) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isJoin(err error, errs []error) bool {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+
+	return err.Error() == strings.Join(msgs, "\n")
+}
+
+// appendJSONValue appends v to b the way [slog.JSONHandler] would render it,
+// preserving the order of group attributes.
+func appendJSONValue(b []byte, v slog.Value) []byte {
+	v = v.Resolve()
+
+	switch v.Kind() {
+	case slog.KindString:
+		return appendJSONString(b, v.String())
+	case slog.KindInt64:
+		return strconv.AppendInt(b, v.Int64(), 10)
+	case slog.KindUint64:
+		return strconv.AppendUint(b, v.Uint64(), 10)
+	case slog.KindFloat64:
+		return appendJSONAny(b, v.Float64())
+	case slog.KindBool:
+		return strconv.AppendBool(b, v.Bool())
+	case slog.KindDuration:
+		return strconv.AppendInt(b, int64(v.Duration()), 10)
+	case slog.KindTime:
+		return appendJSONString(b, v.Time().Format(time.RFC3339Nano))
+	case slog.KindGroup:
+		b = append(b, '{')
+		b, _ = appendJSONAttrs(b, v.Group(), true)
+
+		return append(b, '}')
+	default:
+		if err, ok := v.Any().(error); ok {
+			if _, ok := err.(json.Marshaler); !ok {
+				return appendJSONString(b, err.Error())
+			}
+		}
+
+		return appendJSONAny(b, v.Any())
+	}
+}
+
+// appendJSONAttrs appends attrs as the members of a JSON object. first
+// reports whether no member has been written yet; the updated value is
+// returned so that inlined groups can continue the same object.
+func appendJSONAttrs(b []byte, attrs []slog.Attr, first bool) ([]byte, bool) {
+	for _, attr := range attrs {
+		value := attr.Value.Resolve()
+
+		if attr.Equal(slog.Attr{}) {
+			continue
+		}
+
+		if value.Kind() == slog.KindGroup {
+			if len(value.Group()) == 0 {
+				continue
+			}
+
+			if attr.Key == "" {
+				b, first = appendJSONAttrs(b, value.Group(), first)
+				continue
+			}
+		}
+
+		if !first {
+			b = append(b, ',')
+		}
+		first = false
+
+		b = appendJSONString(b, attr.Key)
+		b = append(b, ':')
+		b = appendJSONValue(b, value)
+	}
+
+	return b, first
+}
+
+func appendJSONString(b []byte, s string) []byte {
+	data, _ := json.Marshal(s)
+	return append(b, data...)
+}
+
+func appendJSONAny(b []byte, v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(b, fmt.Sprintf("!ERROR:%v", err))
+	}
+
+	return append(b, data...)
+}